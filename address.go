@@ -0,0 +1,461 @@
+package tx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrInvalidRecipient is returned when the recipient of a transaction is not a
+// valid address on the destination chain of the transaction.
+var ErrInvalidRecipient = errors.New("invalid recipient")
+
+// An AddressNormaliser validates an address on a chain, and returns the address
+// in its canonical form. Addresses from all networks (mainnet, testnet, and so
+// on) are accepted, because the network is not part of a transaction.
+type AddressNormaliser func(addr string) (string, error)
+
+// addressNormalisers are the address normalisers for each chain.
+var addressNormalisers = map[multichain.Chain]AddressNormaliser{
+	multichain.Arbitrum:          normaliseEVMAddress,
+	multichain.Avalanche:         normaliseEVMAddress,
+	multichain.BinanceSmartChain: normaliseEVMAddress,
+	multichain.Ethereum:          normaliseEVMAddress,
+	multichain.Fantom:            normaliseEVMAddress,
+	multichain.Goerli:            normaliseEVMAddress,
+	multichain.Kovan:             normaliseEVMAddress,
+	multichain.Moonbeam:          normaliseEVMAddress,
+	multichain.Polygon:           normaliseEVMAddress,
+
+	multichain.Bitcoin: utxoAddressNormaliser(
+		[]string{"bc", "tb", "bcrt"},
+		[]byte{0x00}, []byte{0x05}, []byte{0x6f}, []byte{0xc4},
+	),
+	multichain.BitcoinCash: normaliseBitcoinCashAddress,
+	multichain.DigiByte: utxoAddressNormaliser(
+		[]string{"dgb", "dgbt", "dgbrt"},
+		[]byte{0x1e}, []byte{0x3f}, []byte{0x7e}, []byte{0x8c},
+	),
+	multichain.Dogecoin: utxoAddressNormaliser(
+		[]string{"doge", "doget", "dogert"},
+		[]byte{0x1e}, []byte{0x16}, []byte{0x71}, []byte{0xc4},
+	),
+	multichain.Zcash: utxoAddressNormaliser(
+		nil,
+		[]byte{0x1c, 0xb8}, []byte{0x1c, 0xbd}, []byte{0x1d, 0x25}, []byte{0x1c, 0xba},
+	),
+
+	multichain.Filecoin: normaliseFilecoinAddress,
+	multichain.Solana:   normaliseSolanaAddress,
+	multichain.Terra:    normaliseTerraAddress,
+}
+
+// NormaliseAddress validates an address on a chain, and returns the address in
+// its canonical form:
+//   - EVM addresses are hex encoded with a "0x" prefix and an EIP-55 checksum,
+//   - bech32, cashaddr, and Filecoin addresses are lower case, and cashaddr
+//     addresses include their prefix,
+//   - base58 addresses are unchanged.
+//
+// An error wrapping ErrInvalidRecipient is returned if the address is not
+// valid, or if addresses on the chain are not supported.
+func NormaliseAddress(chain multichain.Chain, addr string) (string, error) {
+	normalise, ok := addressNormalisers[chain]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported chain %q", ErrInvalidRecipient, chain)
+	}
+	normalised, err := normalise(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not a %v address: %v", ErrInvalidRecipient, addr, chain, err)
+	}
+	return normalised, nil
+}
+
+// EncodeFilecoinAddress returns the mainnet Filecoin address of a payload. The
+// protocol must be 1 (secp256k1) or 2 (actor), with a 20 byte payload, or 3
+// (BLS), with a 48 byte payload.
+func EncodeFilecoinAddress(protocol byte, payload []byte) (string, error) {
+	length, err := filecoinPayloadLen(protocol + '0')
+	if err != nil {
+		return "", err
+	}
+	if len(payload) != length {
+		return "", fmt.Errorf("expected %v byte payload, got %v bytes", length, len(payload))
+	}
+	checksum, err := filecoinChecksum(protocol, payload)
+	if err != nil {
+		return "", err
+	}
+	return "f" + string(protocol+'0') + filecoinEncoding.EncodeToString(append(append([]byte{}, payload...), checksum...)), nil
+}
+
+// ValidateAddress returns an error wrapping ErrInvalidRecipient if the address
+// is not valid on the chain.
+func ValidateAddress(chain multichain.Chain, addr string) error {
+	_, err := NormaliseAddress(chain, addr)
+	return err
+}
+
+// Recipient returns the "to" input of the transaction, normalised for the
+// destination chain of its selector (see NormaliseAddress).
+func (tx Tx) Recipient() (string, error) {
+	to, ok := tx.Input.Get("to").(pack.String)
+	if !ok {
+		return "", fmt.Errorf("%w: expected input \"to\" of type %v", ErrInvalidRecipient, pack.String("").Type())
+	}
+	destination := tx.Selector.Destination()
+	if destination == "" {
+		return "", fmt.Errorf("%w: selector %v has no destination", ErrInvalidRecipient, tx.Selector)
+	}
+	return NormaliseAddress(destination, string(to))
+}
+
+// ValidateRecipient returns an error wrapping ErrInvalidRecipient if the "to"
+// input of the transaction is not a valid address on the destination chain of
+// its selector.
+func (tx Tx) ValidateRecipient() error {
+	_, err := tx.Recipient()
+	return err
+}
+
+// normaliseEVMAddress accepts hex encoded addresses with a "0x" prefix. Mixed
+// case addresses must have a valid EIP-55 checksum.
+func normaliseEVMAddress(addr string) (string, error) {
+	if !strings.HasPrefix(addr, "0x") {
+		return "", fmt.Errorf("expected 0x prefix")
+	}
+	if _, err := decodeEVMAddress(addr); err != nil {
+		return "", err
+	}
+	checksummed := checksumEVMAddress(addr[2:])
+	if strings.ToLower(addr[2:]) != addr[2:] && strings.ToUpper(addr[2:]) != addr[2:] && checksummed != addr {
+		return "", fmt.Errorf("invalid checksum: expected %v", checksummed)
+	}
+	return checksummed, nil
+}
+
+// checksumEVMAddress returns the EIP-55 checksummed form of a hex encoded
+// address.
+func checksumEVMAddress(hexAddr string) string {
+	lower := strings.ToLower(hexAddr)
+	hash := keccak256([]byte(lower))
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && c <= 'f' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed)
+}
+
+// utxoAddressNormaliser returns a normaliser for a Bitcoin-family chain, which
+// accepts segwit addresses with one of the bech32 prefixes, and base58check
+// addresses with one of the version prefixes.
+func utxoAddressNormaliser(hrps []string, versions ...[]byte) AddressNormaliser {
+	return func(addr string) (string, error) {
+		for _, hrp := range hrps {
+			if len(addr) > len(hrp) && strings.EqualFold(addr[:len(hrp)+1], hrp+"1") {
+				return normaliseSegwitAddress(hrp, addr)
+			}
+		}
+		payload, err := decodeBase58Check(addr)
+		if err != nil {
+			return "", err
+		}
+		for _, version := range versions {
+			if bytes.HasPrefix(payload, version) {
+				if len(payload)-len(version) != 20 {
+					return "", fmt.Errorf("expected 20 byte hash, got %v bytes", len(payload)-len(version))
+				}
+				return addr, nil
+			}
+		}
+		return "", fmt.Errorf("unexpected version %x", payload[0])
+	}
+}
+
+// normaliseSegwitAddress accepts BIP-173 and BIP-350 addresses.
+func normaliseSegwitAddress(hrp, addr string) (string, error) {
+	decodedHRP, data, variant, err := decodeBech32(addr)
+	if err != nil {
+		return "", err
+	}
+	if decodedHRP != hrp {
+		return "", fmt.Errorf("expected prefix %v, got prefix %v", hrp, decodedHRP)
+	}
+	if len(data) == 0 || data[0] > 16 {
+		return "", fmt.Errorf("invalid witness version")
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return "", fmt.Errorf("invalid witness program length %v", len(program))
+	}
+	switch {
+	case data[0] == 0 && len(program) != 20 && len(program) != 32:
+		return "", fmt.Errorf("invalid witness program length %v", len(program))
+	case data[0] == 0 && variant != bech32Const:
+		return "", fmt.Errorf("expected bech32 checksum for witness version 0")
+	case data[0] != 0 && variant != bech32mConst:
+		return "", fmt.Errorf("expected bech32m checksum for witness version %v", data[0])
+	}
+	return strings.ToLower(addr), nil
+}
+
+// normaliseBitcoinCashAddress accepts cashaddr addresses, with or without
+// their prefix, and legacy base58check addresses.
+func normaliseBitcoinCashAddress(addr string) (string, error) {
+	if payload, err := decodeBase58Check(addr); err == nil {
+		switch payload[0] {
+		case 0x00, 0x05, 0x6f, 0xc4:
+			if len(payload) != 21 {
+				return "", fmt.Errorf("expected 20 byte hash, got %v bytes", len(payload)-1)
+			}
+			return addr, nil
+		}
+	}
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return "", fmt.Errorf("mixed case")
+	}
+	addr = strings.ToLower(addr)
+	prefixes := []string{"bitcoincash", "bchtest", "bchreg"}
+	if i := strings.IndexByte(addr, ':'); i >= 0 {
+		prefixes, addr = []string{addr[:i]}, addr[i+1:]
+	}
+	data, err := decodeBech32Data(addr)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < 9 {
+		return "", fmt.Errorf("too short")
+	}
+	for _, prefix := range prefixes {
+		values := make([]byte, 0, len(prefix)+1+len(data))
+		for _, c := range []byte(prefix) {
+			values = append(values, c&0x1f)
+		}
+		values = append(values, 0)
+		values = append(values, data...)
+		if cashAddrPolymod(values) != 0 {
+			continue
+		}
+		payload, err := bech32.ConvertBits(data[:len(data)-8], 5, 8, false)
+		if err != nil {
+			return "", err
+		}
+		sizes := []int{20, 24, 28, 32, 40, 48, 56, 64}
+		if len(payload) == 0 || payload[0]&0x80 != 0 || len(payload)-1 != sizes[payload[0]&0x07] {
+			return "", fmt.Errorf("invalid version or hash length")
+		}
+		return prefix + ":" + addr, nil
+	}
+	return "", fmt.Errorf("invalid checksum")
+}
+
+var filecoinEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// normaliseFilecoinAddress accepts mainnet ("f") and testnet ("t") addresses
+// of the ID, secp256k1, actor, and BLS protocols.
+func normaliseFilecoinAddress(addr string) (string, error) {
+	addr = strings.ToLower(addr)
+	if len(addr) < 3 || (addr[0] != 'f' && addr[0] != 't') {
+		return "", fmt.Errorf("expected f or t prefix")
+	}
+	protocol := addr[1]
+	if protocol == '0' {
+		if len(addr) > 22 || (addr[2] == '0' && len(addr) > 3) {
+			return "", fmt.Errorf("invalid id")
+		}
+		if _, err := strconv.ParseUint(addr[2:], 10, 64); err != nil {
+			return "", fmt.Errorf("invalid id: %v", err)
+		}
+		return addr, nil
+	}
+	length, err := filecoinPayloadLen(protocol)
+	if err != nil {
+		return "", err
+	}
+	data, err := filecoinEncoding.DecodeString(addr[2:])
+	if err != nil {
+		return "", err
+	}
+	if len(data) != length+4 {
+		return "", fmt.Errorf("expected %v byte payload, got %v bytes", length, len(data)-4)
+	}
+	checksum, err := filecoinChecksum(protocol-'0', data[:length])
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(checksum, data[length:]) {
+		return "", fmt.Errorf("invalid checksum")
+	}
+	return addr, nil
+}
+
+// filecoinPayloadLen returns the length of the payload of a Filecoin address
+// with the given protocol character.
+func filecoinPayloadLen(protocol byte) (int, error) {
+	switch protocol {
+	case '1', '2':
+		return 20, nil
+	case '3':
+		return 48, nil
+	default:
+		return 0, fmt.Errorf("unknown protocol %c", protocol)
+	}
+}
+
+// filecoinChecksum returns the 4 byte blake2b checksum of the protocol and
+// payload of a Filecoin address.
+func filecoinChecksum(protocol byte, payload []byte) ([]byte, error) {
+	h, err := blake2b.New(4, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.Write([]byte{protocol})
+	h.Write(payload)
+	return h.Sum(nil), nil
+}
+
+// normaliseSolanaAddress accepts base58 encoded 32 byte public keys.
+func normaliseSolanaAddress(addr string) (string, error) {
+	data := base58.Decode(addr)
+	if len(data) != 32 || base58.Encode(data) != addr {
+		return "", fmt.Errorf("expected base58 encoded 32 byte public key")
+	}
+	return addr, nil
+}
+
+// normaliseTerraAddress accepts bech32 encoded account (20 byte) and contract
+// (32 byte) addresses.
+func normaliseTerraAddress(addr string) (string, error) {
+	hrp, data, variant, err := decodeBech32(addr)
+	if err != nil {
+		return "", err
+	}
+	if hrp != "terra" || variant != bech32Const {
+		return "", fmt.Errorf("expected bech32 address with prefix terra")
+	}
+	payload, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) != 20 && len(payload) != 32 {
+		return "", fmt.Errorf("expected 20 or 32 byte payload, got %v bytes", len(payload))
+	}
+	return strings.ToLower(addr), nil
+}
+
+// decodeBase58Check decodes a base58 string, and verifies and removes its
+// double SHA256 checksum.
+func decodeBase58Check(addr string) ([]byte, error) {
+	data := base58.Decode(addr)
+	if len(data) < 5 {
+		return nil, fmt.Errorf("invalid base58")
+	}
+	payload := data[:len(data)-4]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], data[len(data)-4:]) {
+		return nil, fmt.Errorf("invalid checksum")
+	}
+	return payload, nil
+}
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// decodeBech32 decodes a bech32 or bech32m string, and returns its prefix, its
+// data without the checksum, and the checksum constant of its variant.
+func decodeBech32(str string) (string, []byte, uint32, error) {
+	if strings.ToLower(str) != str && strings.ToUpper(str) != str {
+		return "", nil, 0, fmt.Errorf("mixed case")
+	}
+	str = strings.ToLower(str)
+	sep := strings.LastIndexByte(str, '1')
+	if sep < 1 || sep+7 > len(str) || len(str) > 90 {
+		return "", nil, 0, fmt.Errorf("invalid bech32 length")
+	}
+	hrp := str[:sep]
+	data, err := decodeBech32Data(str[sep+1:])
+	if err != nil {
+		return "", nil, 0, err
+	}
+	values := make([]byte, 0, 2*len(hrp)+1+len(data))
+	for _, c := range []byte(hrp) {
+		values = append(values, c>>5)
+	}
+	values = append(values, 0)
+	for _, c := range []byte(hrp) {
+		values = append(values, c&0x1f)
+	}
+	values = append(values, data...)
+	variant := bech32Polymod(values)
+	if variant != bech32Const && variant != bech32mConst {
+		return "", nil, 0, fmt.Errorf("invalid checksum")
+	}
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// decodeBech32Data maps lower case bech32 characters to their 5-bit values.
+func decodeBech32Data(str string) ([]byte, error) {
+	data := make([]byte, len(str))
+	for i := range str {
+		j := strings.IndexByte(bech32Charset, str[i])
+		if j < 0 {
+			return nil, fmt.Errorf("invalid character %q", str[i])
+		}
+		data[i] = byte(j)
+	}
+	return data, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	gen := [5]uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+	chk := uint64(1)
+	for _, v := range values {
+		b := chk >> 35
+		chk = (chk&0x07ffffffff)<<5 ^ uint64(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk ^ 1
+}
@@ -0,0 +1,225 @@
+package tx_test
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"golang.org/x/crypto/blake2b"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Addresses", func() {
+
+	base58Check := func(version []byte, hash []byte) string {
+		payload := append(append([]byte{}, version...), hash...)
+		first := sha256.Sum256(payload)
+		second := sha256.Sum256(first[:])
+		return base58.Encode(append(payload, second[:4]...))
+	}
+
+	segwit := func(hrp string, version byte, program []byte) string {
+		data, err := bech32.ConvertBits(program, 8, 5, true)
+		Expect(err).ToNot(HaveOccurred())
+		addr, err := bech32.Encode(hrp, append([]byte{version}, data...))
+		Expect(err).ToNot(HaveOccurred())
+		return addr
+	}
+
+	filecoin := func(network byte, protocol byte, payload []byte) string {
+		h, err := blake2b.New(4, nil)
+		Expect(err).ToNot(HaveOccurred())
+		h.Write([]byte{protocol})
+		h.Write(payload)
+		encoding := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(append(payload, h.Sum(nil)...)))
+		return string([]byte{network, '0' + protocol}) + encoding
+	}
+
+	hash20 := make([]byte, 20)
+	for i := range hash20 {
+		hash20[i] = byte(i)
+	}
+	hash32 := make([]byte, 32)
+	for i := range hash32 {
+		hash32[i] = byte(i)
+	}
+
+	type vector struct {
+		chain      multichain.Chain
+		addr       string
+		normalised string
+	}
+
+	valid := []vector{
+		// EIP-55 test vectors.
+		{multichain.Ethereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{multichain.Ethereum, "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{multichain.Polygon, "0xDBF03B407C01E7CD3CBEA99509D93F8DDDC8C6FB", "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{multichain.BinanceSmartChain, "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"},
+
+		{multichain.Bitcoin, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{multichain.Bitcoin, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{multichain.Bitcoin, base58Check([]byte{0x6f}, hash20), base58Check([]byte{0x6f}, hash20)},
+		{multichain.Bitcoin, strings.ToUpper(segwit("bc", 0, hash20)), segwit("bc", 0, hash20)},
+		{multichain.Bitcoin, segwit("tb", 0, hash32), segwit("tb", 0, hash32)},
+		// BIP-350 test vector.
+		{multichain.Bitcoin, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+
+		// Cashaddr test vectors.
+		{multichain.BitcoinCash, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+		{multichain.BitcoinCash, "QPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVY22GDX6A", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+		{multichain.BitcoinCash, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"},
+
+		{multichain.DigiByte, base58Check([]byte{0x1e}, hash20), base58Check([]byte{0x1e}, hash20)},
+		{multichain.DigiByte, segwit("dgb", 0, hash20), segwit("dgb", 0, hash20)},
+		{multichain.Dogecoin, base58Check([]byte{0x71}, hash20), base58Check([]byte{0x71}, hash20)},
+		{multichain.Zcash, base58Check([]byte{0x1c, 0xb8}, hash20), base58Check([]byte{0x1c, 0xb8}, hash20)},
+		{multichain.Zcash, base58Check([]byte{0x1d, 0x25}, hash20), base58Check([]byte{0x1d, 0x25}, hash20)},
+
+		{multichain.Filecoin, "f01024", "f01024"},
+		{multichain.Filecoin, "T0100", "t0100"},
+		{multichain.Filecoin, filecoin('f', 1, hash20), filecoin('f', 1, hash20)},
+		{multichain.Filecoin, strings.ToUpper(filecoin('t', 2, hash20)), filecoin('t', 2, hash20)},
+		{multichain.Filecoin, filecoin('f', 3, make([]byte, 48)), filecoin('f', 3, make([]byte, 48))},
+
+		{multichain.Solana, "11111111111111111111111111111111", "11111111111111111111111111111111"},
+		{multichain.Solana, "So11111111111111111111111111111111111111112", "So11111111111111111111111111111111111111112"},
+
+		{multichain.Terra, segwitData("terra", hash20), segwitData("terra", hash20)},
+		{multichain.Terra, strings.ToUpper(segwitData("terra", hash32)), segwitData("terra", hash32)},
+	}
+
+	invalid := []vector{
+		{multichain.Ethereum, "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ""},
+		{multichain.Ethereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", ""},
+		{multichain.Ethereum, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", ""},
+		{multichain.Ethereum, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beagg", ""},
+
+		{multichain.Bitcoin, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", ""},
+		{multichain.Bitcoin, base58Check([]byte{0x1e}, hash20), ""},
+		{multichain.Bitcoin, base58Check([]byte{0x00}, hash32), ""},
+		{multichain.Bitcoin, segwit("ltc", 0, hash20), ""},
+		{multichain.Bitcoin, segwit("bc", 0, hash20[:16]), ""},
+		// Witness version 1 with a bech32 checksum.
+		{multichain.Bitcoin, segwit("bc", 1, hash32), ""},
+		{multichain.Bitcoin, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jJ0", ""},
+
+		{multichain.BitcoinCash, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", ""},
+		{multichain.BitcoinCash, "bchtest:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", ""},
+		{multichain.BitcoinCash, "bitcoincash:Qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", ""},
+
+		{multichain.Dogecoin, base58Check([]byte{0x00}, hash20), ""},
+		{multichain.Zcash, base58Check([]byte{0x1c}, hash20), ""},
+
+		{multichain.Filecoin, "f0", ""},
+		{multichain.Filecoin, "f00100", ""},
+		{multichain.Filecoin, "x01024", ""},
+		{multichain.Filecoin, "f41024", ""},
+		{multichain.Filecoin, filecoin('f', 1, hash32), ""},
+		{multichain.Filecoin, "f1" + filecoin('f', 2, hash20)[2:], ""},
+
+		{multichain.Solana, "0OIl", ""},
+		{multichain.Solana, base58.Encode(hash20), ""},
+
+		{multichain.Terra, segwitData("cosmos", hash20), ""},
+		{multichain.Terra, segwitData("terra", hash20[:10]), ""},
+
+		{multichain.Chain("Unknown"), "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ""},
+	}
+
+	Context("when normalising valid addresses", func() {
+		It("should return the canonical address", func() {
+			for _, v := range valid {
+				normalised, err := tx.NormaliseAddress(v.chain, v.addr)
+				Expect(err).ToNot(HaveOccurred(), "%v %v", v.chain, v.addr)
+				Expect(normalised).To(Equal(v.normalised), "%v %v", v.chain, v.addr)
+				Expect(tx.ValidateAddress(v.chain, normalised)).To(Succeed())
+			}
+		})
+	})
+
+	Context("when normalising invalid addresses", func() {
+		It("should return an error", func() {
+			for _, v := range invalid {
+				err := tx.ValidateAddress(v.chain, v.addr)
+				Expect(errors.Is(err, tx.ErrInvalidRecipient)).To(BeTrue(), "%v %v", v.chain, v.addr)
+			}
+		})
+	})
+
+	Context("when encoding Filecoin addresses", func() {
+		It("should return a valid mainnet address", func() {
+			for _, v := range []struct {
+				protocol byte
+				payload  []byte
+			}{{1, hash20}, {2, hash20}, {3, make([]byte, 48)}} {
+				addr, err := tx.EncodeFilecoinAddress(v.protocol, v.payload)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(addr).To(Equal(filecoin('f', v.protocol, v.payload)))
+				Expect(tx.ValidateAddress(multichain.Filecoin, addr)).To(Succeed())
+			}
+		})
+
+		It("should return an error for unknown protocols and payload lengths", func() {
+			for _, v := range []struct {
+				protocol byte
+				payload  []byte
+			}{{0, hash20}, {4, hash20}, {255, hash20}, {1, hash32}, {3, hash20}} {
+				_, err := tx.EncodeFilecoinAddress(v.protocol, v.payload)
+				Expect(err).To(HaveOccurred(), "%v %v", v.protocol, len(v.payload))
+			}
+		})
+	})
+
+	Context("when validating the recipient of a transaction", func() {
+		newTx := func(selector tx.Selector, to string) tx.Tx {
+			input, err := pack.Encode(struct {
+				To string `json:"to"`
+			}{to})
+			Expect(err).ToNot(HaveOccurred())
+			transaction, err := tx.NewTx(selector, pack.Typed(input.(pack.Struct)))
+			Expect(err).ToNot(HaveOccurred())
+			return transaction
+		}
+
+		It("should validate against the destination chain", func() {
+			transaction := newTx("BTC/toEthereum", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+			Expect(transaction.ValidateRecipient()).To(Succeed())
+			Expect(transaction.Recipient()).To(Equal("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"))
+
+			transaction = newTx("BTC/fromEthereum", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy")
+			Expect(transaction.ValidateRecipient()).To(Succeed())
+
+			transaction = newTx("BTC/toSolanaFromEthereum", "11111111111111111111111111111111")
+			Expect(transaction.ValidateRecipient()).To(Succeed())
+
+			transaction = newTx("BTC/fromEthereum", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+			Expect(errors.Is(transaction.ValidateRecipient(), tx.ErrInvalidRecipient)).To(BeTrue())
+		})
+
+		It("should return an error if there is no recipient or destination", func() {
+			transaction, err := tx.NewTx("BTC/toEthereum", pack.Typed{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(errors.Is(transaction.ValidateRecipient(), tx.ErrInvalidRecipient)).To(BeTrue())
+
+			transaction = newTx("Ethereum/syncWithChain", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+			Expect(errors.Is(transaction.ValidateRecipient(), tx.ErrInvalidRecipient)).To(BeTrue())
+		})
+	})
+})
+
+func segwitData(hrp string, payload []byte) string {
+	addr, err := bech32.EncodeFromBase256(hrp, payload)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
@@ -0,0 +1,326 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+)
+
+var (
+	// ErrAmountOverflow is returned when the result of an operation on amounts
+	// is too large to be represented by a U256.
+	ErrAmountOverflow = errors.New("amount overflow")
+
+	// ErrAmountUnderflow is returned when the result of an operation on
+	// amounts would be negative.
+	ErrAmountUnderflow = errors.New("amount underflow")
+)
+
+// assetDecimals is the number of decimals used by assets on their origin
+// chains.
+var assetDecimals = map[multichain.Asset]uint8{
+	multichain.ArbETH: 18,
+	multichain.AVAX:   18,
+	multichain.BCH:    8,
+	multichain.BNB:    18,
+	multichain.BTC:    8,
+	multichain.DAI:    18,
+	multichain.DGB:    8,
+	multichain.DOGE:   8,
+	multichain.ETH:    18,
+	multichain.FIL:    18,
+	multichain.FTM:    18,
+	multichain.GETH:   18,
+	multichain.GLMR:   18,
+	multichain.KETH:   18,
+	multichain.LUNA:   6,
+	multichain.MATIC:  18,
+	multichain.REN:    18,
+	multichain.SOL:    9,
+	multichain.USDC:   6,
+	multichain.ZEC:    8,
+}
+
+// maxAmountLen is the maximum length of a string that will be parsed as an
+// amount. No U256 needs more than 78 digits, plus a decimal point.
+const maxAmountLen = 128
+
+var regexAmount = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]+))?$`)
+
+// AssetDecimals returns the number of decimals used by an asset on its origin
+// chain, and whether or not the asset is known.
+func AssetDecimals(asset multichain.Asset) (uint8, bool) {
+	decimals, ok := assetDecimals[asset]
+	return decimals, ok
+}
+
+// A Denomination is the unit in which an amount is expressed: an asset, the
+// chain on which it is held, and the number of decimals used on that chain.
+type Denomination struct {
+	Asset    multichain.Asset `json:"asset"`
+	Chain    multichain.Chain `json:"chain"`
+	Decimals uint8            `json:"decimals"`
+}
+
+// NativeDenomination returns the denomination of an asset on its origin chain.
+func NativeDenomination(asset multichain.Asset) (Denomination, error) {
+	decimals, ok := AssetDecimals(asset)
+	if !ok {
+		return Denomination{}, fmt.Errorf("unknown asset %q", asset)
+	}
+	return Denomination{Asset: asset, Chain: asset.OriginChain(), Decimals: decimals}, nil
+}
+
+// WrappedDenomination returns the denomination of an asset that has been
+// minted to a host chain. Wrapped assets use the same number of decimals as
+// the asset on its origin chain. If a wrapped asset uses a different number of
+// decimals, its denomination can be constructed directly.
+func WrappedDenomination(asset multichain.Asset, host multichain.Chain) (Denomination, error) {
+	denom, err := NativeDenomination(asset)
+	if err != nil {
+		return Denomination{}, err
+	}
+	if host == "" || host == denom.Chain {
+		return Denomination{}, fmt.Errorf("%v is not a host chain for %v", host, asset)
+	}
+	denom.Chain = host
+	return denom, nil
+}
+
+// IsNative returns true if the denomination is for an asset on its origin
+// chain.
+func (denom Denomination) IsNative() bool {
+	return denom.Chain == denom.Asset.OriginChain()
+}
+
+// NewAmount returns an amount of this denomination. The value is in the
+// smallest unit of the denomination.
+func (denom Denomination) NewAmount(value pack.U256) Amount {
+	return Amount{Value: value, Denomination: denom}
+}
+
+// ParseAmount parses a human-readable amount of this denomination, such as
+// "0.015" or "0.015 BTC". An error is returned if the amount has more decimal
+// places than the denomination, or if the asset does not match.
+func (denom Denomination) ParseAmount(str string) (Amount, error) {
+	str = strings.TrimSpace(str)
+	if len(str) > maxAmountLen {
+		return Amount{}, fmt.Errorf("amount is too long: expected len<=%v, got len=%v", maxAmountLen, len(str))
+	}
+	if i := strings.IndexAny(str, " \t"); i >= 0 {
+		asset := multichain.Asset(strings.TrimSpace(str[i:]))
+		if asset != denom.Asset {
+			return Amount{}, fmt.Errorf("expected asset %v, got asset %v", denom.Asset, asset)
+		}
+		str = str[:i]
+	}
+	matches := regexAmount.FindStringSubmatch(str)
+	if matches == nil {
+		return Amount{}, fmt.Errorf("invalid amount %q", str)
+	}
+	whole, frac := matches[1], strings.TrimRight(matches[2], "0")
+	if len(frac) > int(denom.Decimals) {
+		return Amount{}, fmt.Errorf("too many decimal places: expected <=%v, got %v", denom.Decimals, len(frac))
+	}
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(denom.Decimals)-len(frac)), 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", str)
+	}
+	return denom.newAmount(value)
+}
+
+// newAmount returns an amount of this denomination, or an error if the value
+// cannot be represented by a U256.
+func (denom Denomination) newAmount(value *big.Int) (Amount, error) {
+	if value.Sign() < 0 {
+		return Amount{}, ErrAmountUnderflow
+	}
+	if value.Cmp(pack.MaxU256.Int()) > 0 {
+		return Amount{}, ErrAmountOverflow
+	}
+	return Amount{Value: pack.NewU256FromInt(value), Denomination: denom}, nil
+}
+
+func (denom Denomination) String() string {
+	if denom.IsNative() {
+		return fmt.Sprintf("%v (%v decimals)", denom.Asset, denom.Decimals)
+	}
+	return fmt.Sprintf("%v on %v (%v decimals)", denom.Asset, denom.Chain, denom.Decimals)
+}
+
+// An Amount is a value in the smallest unit of a denomination. For example, an
+// amount of 1500000 in the native denomination of BTC is 0.015 BTC.
+type Amount struct {
+	Value pack.U256 `json:"value"`
+	Denomination
+}
+
+// NewAmount returns an amount of an asset in its native denomination.
+func NewAmount(value pack.U256, asset multichain.Asset) (Amount, error) {
+	denom, err := NativeDenomination(asset)
+	if err != nil {
+		return Amount{}, err
+	}
+	return denom.NewAmount(value), nil
+}
+
+// ParseAmount parses a human-readable amount of an asset in its native
+// denomination. The asset must be given, such as "0.015 BTC".
+func ParseAmount(str string) (Amount, error) {
+	fields := strings.Fields(str)
+	if len(fields) != 2 {
+		return Amount{}, fmt.Errorf("invalid amount %q: expected value and asset", str)
+	}
+	denom, err := NativeDenomination(multichain.Asset(fields[1]))
+	if err != nil {
+		return Amount{}, err
+	}
+	return denom.ParseAmount(fields[0])
+}
+
+// Int returns the value of the amount as a big integer.
+func (amount Amount) Int() *big.Int {
+	if amount.Value == (pack.U256{}) {
+		return new(big.Int)
+	}
+	return amount.Value.Int()
+}
+
+// Add returns the sum of two amounts. An error is returned if the amounts have
+// different denominations, or if the sum overflows.
+func (amount Amount) Add(other Amount) (Amount, error) {
+	if err := amount.checkDenomination(other); err != nil {
+		return Amount{}, err
+	}
+	return amount.Denomination.newAmount(new(big.Int).Add(amount.Int(), other.Int()))
+}
+
+// Sub returns the difference of two amounts. An error is returned if the
+// amounts have different denominations, or if the difference is negative.
+func (amount Amount) Sub(other Amount) (Amount, error) {
+	if err := amount.checkDenomination(other); err != nil {
+		return Amount{}, err
+	}
+	return amount.Denomination.newAmount(new(big.Int).Sub(amount.Int(), other.Int()))
+}
+
+// MulDiv returns the amount multiplied by a numerator and divided by a
+// denominator, rounding down. The intermediate product is not limited to 256
+// bits, so only the result can overflow. This is useful for computing fees
+// given in basis points.
+func (amount Amount) MulDiv(num, den uint64) (Amount, error) {
+	if den == 0 {
+		return Amount{}, fmt.Errorf("division by zero")
+	}
+	value := new(big.Int).Mul(amount.Int(), new(big.Int).SetUint64(num))
+	return amount.Denomination.newAmount(value.Div(value, new(big.Int).SetUint64(den)))
+}
+
+// Cmp compares two amounts, and returns -1, 0, or +1. An error is returned if
+// the amounts have different denominations.
+func (amount Amount) Cmp(other Amount) (int, error) {
+	if err := amount.checkDenomination(other); err != nil {
+		return 0, err
+	}
+	return amount.Int().Cmp(other.Int()), nil
+}
+
+// IsZero returns true if the value of the amount is zero.
+func (amount Amount) IsZero() bool {
+	return amount.Int().Sign() == 0
+}
+
+// Convert the amount to another denomination of the same asset. An error is
+// returned if the amount cannot be represented exactly in the other
+// denomination.
+func (amount Amount) Convert(denom Denomination) (Amount, error) {
+	if denom.Asset != amount.Asset {
+		return Amount{}, fmt.Errorf("cannot convert %v to %v", amount.Asset, denom.Asset)
+	}
+	value := amount.Int()
+	switch {
+	case denom.Decimals > amount.Decimals:
+		value.Mul(value, pow10(denom.Decimals-amount.Decimals))
+	case denom.Decimals < amount.Decimals:
+		rem := new(big.Int)
+		value.QuoRem(value, pow10(amount.Decimals-denom.Decimals), rem)
+		if rem.Sign() != 0 {
+			return Amount{}, fmt.Errorf("cannot convert %v to %v decimals without losing precision", amount, denom.Decimals)
+		}
+	}
+	return denom.newAmount(value)
+}
+
+// ToNative converts the amount to the native denomination of its asset.
+func (amount Amount) ToNative() (Amount, error) {
+	denom, err := NativeDenomination(amount.Asset)
+	if err != nil {
+		return Amount{}, err
+	}
+	return amount.Convert(denom)
+}
+
+// ToWrapped converts the amount to the wrapped denomination of its asset on a
+// host chain.
+func (amount Amount) ToWrapped(host multichain.Chain) (Amount, error) {
+	denom, err := WrappedDenomination(amount.Asset, host)
+	if err != nil {
+		return Amount{}, err
+	}
+	return amount.Convert(denom)
+}
+
+// String returns the amount in a human-readable format, such as "0.015 BTC".
+// Trailing zeros are not included.
+func (amount Amount) String() string {
+	digits := amount.Int().String()
+	decimals := int(amount.Decimals)
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return fmt.Sprintf("%v %v", whole, amount.Asset)
+	}
+	return fmt.Sprintf("%v.%v %v", whole, frac, amount.Asset)
+}
+
+func (amount Amount) checkDenomination(other Amount) error {
+	if amount.Denomination != other.Denomination {
+		return fmt.Errorf("mismatched denominations: %v and %v", amount.Denomination, other.Denomination)
+	}
+	return nil
+}
+
+// Amount returns the "amount" input of the transaction in the denomination of
+// its source chain. For example, the amount of a "BTC/toEthereum" transaction
+// is in native BTC, and the amount of a "BTC/fromEthereum" transaction is in
+// BTC wrapped on Ethereum.
+func (tx Tx) Amount() (Amount, error) {
+	value, ok := tx.Input.Get("amount").(pack.U256)
+	if !ok {
+		return Amount{}, fmt.Errorf("expected input \"amount\" of type %v", pack.U256{}.Type())
+	}
+	asset, source := tx.Selector.Asset(), tx.Selector.Source()
+	if source == asset.OriginChain() {
+		denom, err := NativeDenomination(asset)
+		if err != nil {
+			return Amount{}, err
+		}
+		return denom.NewAmount(value), nil
+	}
+	denom, err := WrappedDenomination(asset, source)
+	if err != nil {
+		return Amount{}, err
+	}
+	return denom.NewAmount(value), nil
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,176 @@
+package tx_test
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing/quick"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Amount", func() {
+
+	mustParse := func(str string) tx.Amount {
+		amount, err := tx.ParseAmount(str)
+		Expect(err).ToNot(HaveOccurred())
+		return amount
+	}
+
+	Context("when parsing and formatting", func() {
+		It("should use the decimals of the asset", func() {
+			amount := mustParse("0.015 BTC")
+			Expect(amount.Value).To(Equal(pack.NewU256FromUint64(1500000)))
+			Expect(amount.Asset).To(Equal(multichain.BTC))
+			Expect(amount.Chain).To(Equal(multichain.Bitcoin))
+			Expect(amount.Decimals).To(Equal(uint8(8)))
+			Expect(amount.String()).To(Equal("0.015 BTC"))
+
+			Expect(mustParse("1 FIL").Value).To(Equal(pack.NewU256FromUint64(1000000000000000000)))
+			Expect(mustParse("1 FIL").String()).To(Equal("1 FIL"))
+			Expect(mustParse("2.500000 LUNA").Value).To(Equal(pack.NewU256FromUint64(2500000)))
+			Expect(mustParse("2.500000 LUNA").String()).To(Equal("2.5 LUNA"))
+			Expect(mustParse("0 ZEC").String()).To(Equal("0 ZEC"))
+			Expect(mustParse("0.00000001 BTC").String()).To(Equal("0.00000001 BTC"))
+		})
+
+		It("should return itself", func() {
+			f := func(value pack.U256, decimals uint8) bool {
+				denom := tx.Denomination{Asset: multichain.BTC, Chain: multichain.Ethereum, Decimals: decimals % 80}
+				amount := denom.NewAmount(value)
+				parsed, err := denom.ParseAmount(amount.String())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed.Int()).To(Equal(amount.Int()))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should return an error for invalid amounts", func() {
+			for _, str := range []string{
+				"", "BTC", "1", "1 XYZ", "1 BTC BTC", "-1 BTC", "1.2.3 BTC", ".5 BTC", "1. BTC", "1e8 BTC",
+				"0.000000001 BTC",
+				"1000000000000000000000000000000000000000000000000000000000000000000000000 BTC",
+			} {
+				_, err := tx.ParseAmount(str)
+				Expect(err).To(HaveOccurred(), str)
+			}
+
+			denom, err := tx.NativeDenomination(multichain.BTC)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = denom.ParseAmount("1 ZEC")
+			Expect(err).To(HaveOccurred())
+			amount, err := denom.ParseAmount("1.5")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amount.String()).To(Equal("1.5 BTC"))
+		})
+	})
+
+	Context("when doing arithmetic", func() {
+		It("should return the correct result", func() {
+			sum, err := mustParse("0.015 BTC").Add(mustParse("1.985 BTC"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sum.String()).To(Equal("2 BTC"))
+			diff, err := sum.Sub(mustParse("0.5 BTC"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.String()).To(Equal("1.5 BTC"))
+			fee, err := diff.MulDiv(15, 10000)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fee.String()).To(Equal("0.00225 BTC"))
+			cmp, err := fee.Cmp(diff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cmp).To(Equal(-1))
+			Expect(tx.Amount{Denomination: diff.Denomination}.IsZero()).To(BeTrue())
+		})
+
+		It("should return an error on overflow and underflow", func() {
+			max := mustParse("1 BTC").Denomination.NewAmount(pack.MaxU256)
+			_, err := max.Add(mustParse("0.00000001 BTC"))
+			Expect(errors.Is(err, tx.ErrAmountOverflow)).To(BeTrue())
+			_, err = max.MulDiv(2, 1)
+			Expect(errors.Is(err, tx.ErrAmountOverflow)).To(BeTrue())
+			half, err := max.MulDiv(1, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(half.Int()).To(Equal(new(big.Int).Rsh(pack.MaxU256.Int(), 1)))
+			_, err = mustParse("1 BTC").Sub(mustParse("2 BTC"))
+			Expect(errors.Is(err, tx.ErrAmountUnderflow)).To(BeTrue())
+			_, err = max.MulDiv(1, 0)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for mismatched denominations", func() {
+			_, err := mustParse("1 BTC").Add(mustParse("1 ZEC"))
+			Expect(err).To(HaveOccurred())
+			wrapped, err := mustParse("1 BTC").ToWrapped(multichain.Ethereum)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = mustParse("1 BTC").Sub(wrapped)
+			Expect(err).To(HaveOccurred())
+			_, err = mustParse("1 BTC").Cmp(wrapped)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when converting between native and wrapped amounts", func() {
+		It("should preserve the amount", func() {
+			native := mustParse("0.015 BTC")
+			wrapped, err := native.ToWrapped(multichain.Solana)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wrapped.Chain).To(Equal(multichain.Solana))
+			Expect(wrapped.IsNative()).To(BeFalse())
+			Expect(wrapped.Value).To(Equal(native.Value))
+			back, err := wrapped.ToNative()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(back).To(Equal(native))
+
+			_, err = native.ToWrapped(multichain.Bitcoin)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should rescale when the decimals are different", func() {
+			native := mustParse("1.5 FIL")
+			denom := tx.Denomination{Asset: multichain.FIL, Chain: multichain.Solana, Decimals: 9}
+			wrapped, err := native.Convert(denom)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wrapped.Value).To(Equal(pack.NewU256FromUint64(1500000000)))
+			back, err := wrapped.ToNative()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(back.Value).To(Equal(native.Value))
+
+			_, err = mustParse("0.0000000001 FIL").Convert(denom)
+			Expect(err).To(HaveOccurred())
+			_, err = native.Convert(tx.Denomination{Asset: multichain.BTC, Chain: multichain.Solana, Decimals: 8})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when getting the amount of a transaction", func() {
+		It("should use the denomination of the source chain", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			input := txutil.RandomTxInput(r)
+
+			transaction, err := tx.NewTx("BTC/toEthereum", input)
+			Expect(err).ToNot(HaveOccurred())
+			amount, err := transaction.Amount()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amount.Value).To(Equal(input.Get("amount")))
+			Expect(amount.IsNative()).To(BeTrue())
+
+			transaction, err = tx.NewTx("BTC/toSolanaFromEthereum", input)
+			Expect(err).ToNot(HaveOccurred())
+			amount, err = transaction.Amount()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amount.Chain).To(Equal(multichain.Ethereum))
+
+			transaction, err = tx.NewTx("BTC/toEthereum", pack.NewTyped("amount", pack.NewU64(1)))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = transaction.Amount()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
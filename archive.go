@@ -0,0 +1,498 @@
+package tx
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/renproject/id"
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// ArchiveVersion is the version of the archive format that is written by the
+// ArchiveWriter.
+const ArchiveVersion = uint16(1)
+
+// archiveBlockSize is the number of uncompressed bytes after which a block is
+// compressed and written. Larger blocks compress better, but more data must be
+// decompressed to access a single transaction.
+const archiveBlockSize = 64 * 1024
+
+// An archive is a compact, read-only collection of transactions that supports
+// random access. Selectors and the layouts of input and output structs are
+// interned into a dictionary, so that each transaction only stores its values.
+// Transactions are grouped into blocks, and each block is compressed with
+// DEFLATE (RFC 1951):
+//
+//	header:     8 bytes ("RENTXARC"), then uint16 (big-endian) version
+//	blocks:     compressed records
+//	dictionary: compressed archiveDictionary
+//	index:      surge encoded archiveIndex
+//	footer:     uint64 (big-endian) offset of the index, then 8 bytes ("RENTXARC")
+//
+// Each record is the surge encoding of the version, the index of the selector,
+// the index of the input layout, the input values, the index of the output
+// layout, and the output values. Hashes are only stored in the index.
+var (
+	archiveMagic = []byte("RENTXARC")
+
+	archiveHeaderLen = len(archiveMagic) + 2
+	archiveFooterLen = 8 + len(archiveMagic)
+)
+
+// ErrNotInArchive is returned when a transaction cannot be found in an
+// archive.
+var ErrNotInArchive = errors.New("tx not in archive")
+
+// archiveBlock is the location of a compressed section of an archive.
+type archiveBlock struct {
+	Offset  uint64 // Offset of the compressed data from the start of the archive.
+	Size    uint32 // Length of the compressed data.
+	RawSize uint32 // Length of the data after it has been decompressed.
+}
+
+// archiveEntry is the location of a transaction in an archive.
+type archiveEntry struct {
+	Hash   id.Hash
+	Block  uint32 // Index of the block that contains the record.
+	Offset uint32 // Offset of the record in the decompressed block.
+}
+
+// archiveDictionary contains the values that are interned by an archive.
+// Layouts are binary encoded struct types.
+type archiveDictionary struct {
+	Selectors []Selector
+	Layouts   [][]byte
+}
+
+// archiveIndex contains the locations of all data in an archive. Entries are
+// in the order that transactions were written.
+type archiveIndex struct {
+	Dictionary archiveBlock
+	Blocks     []archiveBlock
+	Entries    []archiveEntry
+}
+
+// An ArchiveWriter writes transactions to an archive. Transactions are written
+// in blocks, and the archive is not complete until the writer is closed.
+type ArchiveWriter struct {
+	w      io.Writer
+	offset uint64
+
+	selectors map[Selector]uint32
+	layouts   map[string]uint32
+	dict      archiveDictionary
+	index     archiveIndex
+
+	block      []byte
+	compressed *bytes.Buffer
+	compressor *flate.Writer
+	closed     bool
+}
+
+// NewArchiveWriter returns an ArchiveWriter that writes to the given writer.
+// The header of the archive is written immediately.
+func NewArchiveWriter(w io.Writer) (*ArchiveWriter, error) {
+	header := make([]byte, archiveHeaderLen)
+	copy(header, archiveMagic)
+	binary.BigEndian.PutUint16(header[len(archiveMagic):], ArchiveVersion)
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("writing header: %v", err)
+	}
+	compressed := new(bytes.Buffer)
+	compressor, err := flate.NewWriter(compressed, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{
+		w:      w,
+		offset: uint64(archiveHeaderLen),
+
+		selectors: map[Selector]uint32{},
+		layouts:   map[string]uint32{},
+		dict:      archiveDictionary{Selectors: []Selector{}, Layouts: [][]byte{}},
+		index:     archiveIndex{Blocks: []archiveBlock{}, Entries: []archiveEntry{}},
+
+		compressed: compressed,
+		compressor: compressor,
+	}, nil
+}
+
+// Write a transaction to the archive. The transaction is buffered until its
+// block is full, or the writer is closed.
+func (w *ArchiveWriter) Write(tx Tx) error {
+	if w.closed {
+		return fmt.Errorf("archive is closed")
+	}
+	selector := w.internSelector(tx.Selector)
+	input, err := w.internLayout(tx.Input)
+	if err != nil {
+		return fmt.Errorf("interning input layout: %v", err)
+	}
+	output, err := w.internLayout(tx.Output)
+	if err != nil {
+		return fmt.Errorf("interning output layout: %v", err)
+	}
+
+	n := surge.SizeHintString(string(tx.Version)) +
+		surge.SizeHintU32 + surge.SizeHintU32 + pack.Struct(tx.Input).SizeHint() +
+		surge.SizeHintU32 + pack.Struct(tx.Output).SizeHint()
+	offset := len(w.block)
+	w.block = append(w.block, make([]byte, n)...)
+	buf, rem := w.block[offset:], surge.MaxBytes
+	if buf, rem, err = surge.MarshalString(string(tx.Version), buf, rem); err != nil {
+		return fmt.Errorf("marshaling version: %v", err)
+	}
+	if buf, rem, err = surge.MarshalU32(selector, buf, rem); err != nil {
+		return fmt.Errorf("marshaling selector: %v", err)
+	}
+	if buf, rem, err = surge.MarshalU32(input, buf, rem); err != nil {
+		return fmt.Errorf("marshaling input layout: %v", err)
+	}
+	if buf, rem, err = pack.Struct(tx.Input).Marshal(buf, rem); err != nil {
+		return fmt.Errorf("marshaling input: %v", err)
+	}
+	if buf, rem, err = surge.MarshalU32(output, buf, rem); err != nil {
+		return fmt.Errorf("marshaling output layout: %v", err)
+	}
+	if _, _, err = pack.Struct(tx.Output).Marshal(buf, rem); err != nil {
+		return fmt.Errorf("marshaling output: %v", err)
+	}
+
+	w.index.Entries = append(w.index.Entries, archiveEntry{
+		Hash:   tx.Hash,
+		Block:  uint32(len(w.index.Blocks)),
+		Offset: uint32(offset),
+	})
+	if len(w.block) >= archiveBlockSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close the writer. Any buffered transactions are written, followed by the
+// dictionary and the index. The underlying writer is not closed.
+func (w *ArchiveWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	dict, err := surge.ToBinary(w.dict)
+	if err != nil {
+		return fmt.Errorf("marshaling dictionary: %v", err)
+	}
+	if w.index.Dictionary, err = w.writeCompressed(dict); err != nil {
+		return fmt.Errorf("writing dictionary: %v", err)
+	}
+	index, err := surge.ToBinary(w.index)
+	if err != nil {
+		return fmt.Errorf("marshaling index: %v", err)
+	}
+	footer := make([]byte, archiveFooterLen)
+	binary.BigEndian.PutUint64(footer, w.offset)
+	copy(footer[8:], archiveMagic)
+	if _, err := w.w.Write(append(index, footer...)); err != nil {
+		return fmt.Errorf("writing index: %v", err)
+	}
+	return nil
+}
+
+// flush compresses and writes the current block, if it is not empty.
+func (w *ArchiveWriter) flush() error {
+	if len(w.block) == 0 {
+		return nil
+	}
+	block, err := w.writeCompressed(w.block)
+	if err != nil {
+		return fmt.Errorf("writing block: %v", err)
+	}
+	w.index.Blocks = append(w.index.Blocks, block)
+	w.block = w.block[:0]
+	return nil
+}
+
+func (w *ArchiveWriter) writeCompressed(data []byte) (archiveBlock, error) {
+	w.compressed.Reset()
+	w.compressor.Reset(w.compressed)
+	if _, err := w.compressor.Write(data); err != nil {
+		return archiveBlock{}, err
+	}
+	if err := w.compressor.Close(); err != nil {
+		return archiveBlock{}, err
+	}
+	block := archiveBlock{
+		Offset:  w.offset,
+		Size:    uint32(w.compressed.Len()),
+		RawSize: uint32(len(data)),
+	}
+	if _, err := w.w.Write(w.compressed.Bytes()); err != nil {
+		return archiveBlock{}, err
+	}
+	w.offset += uint64(block.Size)
+	return block, nil
+}
+
+func (w *ArchiveWriter) internSelector(selector Selector) uint32 {
+	if i, ok := w.selectors[selector]; ok {
+		return i
+	}
+	i := uint32(len(w.dict.Selectors))
+	w.selectors[selector] = i
+	w.dict.Selectors = append(w.dict.Selectors, selector)
+	return i
+}
+
+func (w *ArchiveWriter) internLayout(typed pack.Typed) (uint32, error) {
+	t := pack.Struct(typed).Type()
+	layout := make([]byte, pack.SizeHintType(t))
+	if _, _, err := pack.MarshalType(t, layout, len(layout)); err != nil {
+		return 0, err
+	}
+	if i, ok := w.layouts[string(layout)]; ok {
+		return i, nil
+	}
+	i := uint32(len(w.dict.Layouts))
+	w.layouts[string(layout)] = i
+	w.dict.Layouts = append(w.dict.Layouts, layout)
+	return i, nil
+}
+
+// WriteArchive writes the transactions to the writer as a complete archive.
+func WriteArchive(w io.Writer, txs []Tx) error {
+	archive, err := NewArchiveWriter(w)
+	if err != nil {
+		return err
+	}
+	for i, tx := range txs {
+		if err := archive.Write(tx); err != nil {
+			return fmt.Errorf("writing tx %v: %v", i, err)
+		}
+	}
+	return archive.Close()
+}
+
+// An Archive provides random access to the transactions in an archive. Only
+// the dictionary and index are held in memory, and blocks are read and
+// decompressed as they are needed.
+type Archive struct {
+	r         io.ReaderAt
+	selectors []Selector
+	layouts   []pack.Type
+	index     archiveIndex
+	hashes    map[id.Hash]int
+}
+
+// OpenArchive reads the dictionary and index of an archive, so that the
+// transactions in the archive can be accessed. The size is the total length of
+// the archive in bytes.
+func OpenArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	if size < int64(archiveHeaderLen+archiveFooterLen) {
+		return nil, fmt.Errorf("archive is too small: size=%v", size)
+	}
+	header := make([]byte, archiveHeaderLen)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("reading header: %v", err)
+	}
+	if !bytes.Equal(header[:len(archiveMagic)], archiveMagic) {
+		return nil, fmt.Errorf("bad archive header")
+	}
+	if version := binary.BigEndian.Uint16(header[len(archiveMagic):]); version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %v", version)
+	}
+
+	footer := make([]byte, archiveFooterLen)
+	if _, err := r.ReadAt(footer, size-int64(archiveFooterLen)); err != nil {
+		return nil, fmt.Errorf("reading footer: %v", err)
+	}
+	if !bytes.Equal(footer[8:], archiveMagic) {
+		return nil, fmt.Errorf("bad archive footer")
+	}
+	indexOffset := binary.BigEndian.Uint64(footer)
+	indexEnd := uint64(size) - uint64(archiveFooterLen)
+	if indexOffset < uint64(archiveHeaderLen) || indexOffset > indexEnd || indexEnd-indexOffset > uint64(surge.MaxBytes) {
+		return nil, fmt.Errorf("bad index offset %v", indexOffset)
+	}
+	data := make([]byte, indexEnd-indexOffset)
+	if _, err := r.ReadAt(data, int64(indexOffset)); err != nil {
+		return nil, fmt.Errorf("reading index: %v", err)
+	}
+
+	archive := &Archive{r: r}
+	if err := surge.FromBinary(&archive.index, data); err != nil {
+		return nil, fmt.Errorf("unmarshaling index: %v", err)
+	}
+	for i, block := range append([]archiveBlock{archive.index.Dictionary}, archive.index.Blocks...) {
+		if block.Offset < uint64(archiveHeaderLen) || block.Offset+uint64(block.Size) > indexOffset || block.RawSize > uint32(surge.MaxBytes) {
+			return nil, fmt.Errorf("bad block %v: offset=%v, size=%v, raw size=%v", i, block.Offset, block.Size, block.RawSize)
+		}
+	}
+	archive.hashes = make(map[id.Hash]int, len(archive.index.Entries))
+	for i, entry := range archive.index.Entries {
+		if entry.Block >= uint32(len(archive.index.Blocks)) || entry.Offset >= archive.index.Blocks[entry.Block].RawSize {
+			return nil, fmt.Errorf("bad entry %v: block=%v, offset=%v", i, entry.Block, entry.Offset)
+		}
+		if _, ok := archive.hashes[entry.Hash]; !ok {
+			archive.hashes[entry.Hash] = i
+		}
+	}
+
+	data, err := archive.readBlock(archive.index.Dictionary)
+	if err != nil {
+		return nil, fmt.Errorf("reading dictionary: %v", err)
+	}
+	dict := archiveDictionary{}
+	if err := surge.FromBinary(&dict, data); err != nil {
+		return nil, fmt.Errorf("unmarshaling dictionary: %v", err)
+	}
+	archive.selectors = dict.Selectors
+	archive.layouts = make([]pack.Type, len(dict.Layouts))
+	for i, layout := range dict.Layouts {
+		if _, _, err := pack.UnmarshalType(&archive.layouts[i], layout, len(layout)); err != nil {
+			return nil, fmt.Errorf("unmarshaling layout %v: %v", i, err)
+		}
+		if archive.layouts[i].Kind() != pack.KindStruct {
+			return nil, fmt.Errorf("unmarshaling layout %v: expected kind \"struct\", got kind \"%v\"", i, archive.layouts[i].Kind())
+		}
+	}
+	return archive, nil
+}
+
+// ReadArchive reads all of the transactions from an archive that is held in
+// memory.
+func ReadArchive(data []byte) ([]Tx, error) {
+	archive, err := OpenArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	return archive.ReadAll()
+}
+
+// Len returns the number of transactions in the archive.
+func (archive *Archive) Len() int {
+	return len(archive.index.Entries)
+}
+
+// Hashes returns the hashes of all transactions in the archive, in the order
+// that they were written.
+func (archive *Archive) Hashes() []id.Hash {
+	hashes := make([]id.Hash, len(archive.index.Entries))
+	for i, entry := range archive.index.Entries {
+		hashes[i] = entry.Hash
+	}
+	return hashes
+}
+
+// At returns the i-th transaction in the archive.
+func (archive *Archive) At(i int) (Tx, error) {
+	if i < 0 || i >= len(archive.index.Entries) {
+		return Tx{}, fmt.Errorf("index out of range: index=%v, len=%v", i, len(archive.index.Entries))
+	}
+	entry := archive.index.Entries[i]
+	block, err := archive.readBlock(archive.index.Blocks[entry.Block])
+	if err != nil {
+		return Tx{}, fmt.Errorf("reading block %v: %v", entry.Block, err)
+	}
+	return archive.decode(entry, block)
+}
+
+// Get returns the transaction with the given hash. If there is more than one
+// transaction with the hash, the first one is returned. An error wrapping
+// ErrNotInArchive is returned if there is no transaction with the hash.
+func (archive *Archive) Get(hash id.Hash) (Tx, error) {
+	i, ok := archive.hashes[hash]
+	if !ok {
+		return Tx{}, fmt.Errorf("%w: hash=%v", ErrNotInArchive, hash)
+	}
+	return archive.At(i)
+}
+
+// ReadAll returns all of the transactions in the archive, in the order that
+// they were written. Each block is only decompressed once.
+func (archive *Archive) ReadAll() ([]Tx, error) {
+	txs := make([]Tx, 0, len(archive.index.Entries))
+	var block []byte
+	for i, entry := range archive.index.Entries {
+		if i == 0 || entry.Block != archive.index.Entries[i-1].Block {
+			var err error
+			if block, err = archive.readBlock(archive.index.Blocks[entry.Block]); err != nil {
+				return nil, fmt.Errorf("reading block %v: %v", entry.Block, err)
+			}
+		}
+		tx, err := archive.decode(entry, block)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// readBlock reads and decompresses a block.
+func (archive *Archive) readBlock(block archiveBlock) ([]byte, error) {
+	r := flate.NewReader(io.NewSectionReader(archive.r, int64(block.Offset), int64(block.Size)))
+	defer r.Close()
+	data := make([]byte, block.RawSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("decompressing: %v", err)
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		return nil, fmt.Errorf("decompressing: expected %v bytes", block.RawSize)
+	}
+	return data, nil
+}
+
+// decode the record of an entry from its decompressed block.
+func (archive *Archive) decode(entry archiveEntry, block []byte) (Tx, error) {
+	var err error
+	var version string
+	var selector, input, output uint32
+	tx := Tx{Hash: entry.Hash}
+	buf, rem := block[entry.Offset:], surge.MaxBytes
+	if buf, rem, err = surge.UnmarshalString(&version, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling version of %v: %v", entry.Hash, err)
+	}
+	tx.Version = Version(version)
+	if buf, rem, err = surge.UnmarshalU32(&selector, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling selector of %v: %v", entry.Hash, err)
+	}
+	if selector >= uint32(len(archive.selectors)) {
+		return Tx{}, fmt.Errorf("unmarshaling selector of %v: unknown selector %v", entry.Hash, selector)
+	}
+	tx.Selector = archive.selectors[selector]
+	if buf, rem, err = surge.UnmarshalU32(&input, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling input layout of %v: %v", entry.Hash, err)
+	}
+	if tx.Input, buf, rem, err = archive.decodeTyped(input, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling input of %v: %v", entry.Hash, err)
+	}
+	if buf, rem, err = surge.UnmarshalU32(&output, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling output layout of %v: %v", entry.Hash, err)
+	}
+	if tx.Output, _, _, err = archive.decodeTyped(output, buf, rem); err != nil {
+		return Tx{}, fmt.Errorf("unmarshaling output of %v: %v", entry.Hash, err)
+	}
+	return tx, nil
+}
+
+func (archive *Archive) decodeTyped(layout uint32, buf []byte, rem int) (pack.Typed, []byte, int, error) {
+	if layout >= uint32(len(archive.layouts)) {
+		return nil, buf, rem, fmt.Errorf("unknown layout %v", layout)
+	}
+	v, buf, rem, err := archive.layouts[layout].UnmarshalValue(buf, rem)
+	if err != nil {
+		return nil, buf, rem, err
+	}
+	s, ok := v.(pack.Struct)
+	if !ok {
+		return nil, buf, rem, fmt.Errorf("expected kind \"struct\", got kind \"%v\"", v.Type().Kind())
+	}
+	return pack.Typed(s), buf, rem, nil
+}
@@ -0,0 +1,117 @@
+package tx_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archive", func() {
+
+	writeArchive := func(txs []tx.Tx) []byte {
+		buf := new(bytes.Buffer)
+		Expect(tx.WriteArchive(buf, txs)).To(Succeed())
+		return buf.Bytes()
+	}
+
+	expectEqual := func(got, expected tx.Tx) {
+		gotData, err := surge.ToBinary(got)
+		Expect(err).ToNot(HaveOccurred())
+		expectedData, err := surge.ToBinary(expected)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gotData).To(Equal(expectedData))
+	}
+
+	Context("when writing and then reading an archive", func() {
+		It("should return the same transactions in the same order", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := append(txutil.RandomGoodTxs(r, 1000), txutil.RandomBadTxs(r, 100)...)
+			r.Shuffle(len(txs), func(i, j int) { txs[i], txs[j] = txs[j], txs[i] })
+
+			got, err := tx.ReadArchive(writeArchive(txs))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(got).To(HaveLen(len(txs)))
+			for i := range txs {
+				expectEqual(got[i], txs[i])
+			}
+		})
+
+		It("should be smaller than the binary encoding", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxs(r, 1000)
+			data, err := surge.ToBinary(txs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(writeArchive(txs))).To(BeNumerically("<", len(data)))
+		})
+	})
+
+	Context("when the archive is empty", func() {
+		It("should return no transactions", func() {
+			got, err := tx.ReadArchive(writeArchive(nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(got).To(BeEmpty())
+		})
+	})
+
+	Context("when accessing transactions randomly", func() {
+		It("should return the transaction at an index or with a hash", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxs(r, 1000)
+			data := writeArchive(txs)
+
+			archive, err := tx.OpenArchive(bytes.NewReader(data), int64(len(data)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(archive.Len()).To(Equal(len(txs)))
+			Expect(archive.Hashes()).To(HaveLen(len(txs)))
+			for k := 0; k < 100; k++ {
+				i := r.Intn(len(txs))
+				got, err := archive.At(i)
+				Expect(err).ToNot(HaveOccurred())
+				expectEqual(got, txs[i])
+				got, err = archive.Get(txs[i].Hash)
+				Expect(err).ToNot(HaveOccurred())
+				expectEqual(got, txs[i])
+			}
+
+			_, err = archive.At(len(txs))
+			Expect(err).To(HaveOccurred())
+			_, err = archive.Get(txutil.RandomTxHash(r))
+			Expect(errors.Is(err, tx.ErrNotInArchive)).To(BeTrue())
+		})
+	})
+
+	Context("when writing to a closed archive", func() {
+		It("should return an error", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			w, err := tx.NewArchiveWriter(new(bytes.Buffer))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			Expect(w.Write(txutil.RandomGoodTx(r))).ToNot(Succeed())
+		})
+	})
+
+	Context("when the archive is corrupt", func() {
+		It("should return an error instead of panicking", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			data := writeArchive(txutil.RandomGoodTxs(r, 100))
+
+			_, err := tx.ReadArchive(data[:len(data)-1])
+			Expect(err).To(HaveOccurred())
+			_, err = tx.ReadArchive(data[1:])
+			Expect(err).To(HaveOccurred())
+
+			for k := 0; k < 1000; k++ {
+				corrupt := append([]byte{}, data...)
+				corrupt[r.Intn(len(corrupt))] ^= byte(1 + r.Intn(255))
+				Expect(func() { tx.ReadArchive(corrupt) }).ToNot(Panic())
+			}
+		})
+	})
+})
@@ -0,0 +1,224 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CanonicalJSON returns the canonical JSON encoding of the transaction. Two
+// transactions that are equal will always have byte-for-byte identical
+// canonical encodings, irrespective of the SDK that produced them. See
+// CanonicalizeJSON for the rules that define the canonical encoding.
+func (tx Tx) CanonicalJSON() ([]byte, error) {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	return CanonicalizeJSON(data)
+}
+
+// UnmarshalTxJSON unmarshals a transaction from JSON. When strict is true, an
+// error is returned if the data is not the canonical JSON encoding of the
+// transaction. This rejects data that is equivalent to the canonical encoding,
+// but not byte-for-byte identical to it (for example, because it has extra
+// whitespace, unsorted keys, padded base64 bytes, or numbers with leading
+// zeros).
+func UnmarshalTxJSON(data []byte, strict bool) (Tx, error) {
+	tx := Tx{}
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return Tx{}, err
+	}
+	if strict {
+		canonical, err := tx.CanonicalJSON()
+		if err != nil {
+			return Tx{}, err
+		}
+		if !bytes.Equal(data, canonical) {
+			return Tx{}, fmt.Errorf("non-canonical json: expected %s, got %s", canonical, data)
+		}
+	}
+	return tx, nil
+}
+
+// ValidateCanonicalJSON returns an error if the data is not canonical JSON. It
+// only checks the structure of the JSON, and knows nothing about the types
+// that are encoded. Use UnmarshalTxJSON in strict mode to also check that the
+// values inside a transaction are canonically encoded.
+func ValidateCanonicalJSON(data []byte) error {
+	canonical, err := CanonicalizeJSON(data)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, canonical) {
+		return fmt.Errorf("non-canonical json: expected %s, got %s", canonical, data)
+	}
+	return nil
+}
+
+// CanonicalizeJSON re-encodes arbitrary JSON into its canonical form. In the
+// canonical form:
+//
+//   - there is no insignificant whitespace,
+//   - object keys are sorted by their UTF-8 encoding, and must be unique,
+//   - strings are escaped minimally, without escaping HTML characters,
+//   - integers are written in decimal without exponents, fractions, or negative
+//     zero (integers written with fractions or exponents are normalised when
+//     they are exactly representable by a 64-bit float), and
+//   - other numbers are written in the shortest form that round-trips through
+//     a 64-bit float.
+//
+// An error is returned if the data is not valid JSON, or if it contains
+// duplicate object keys. Invalid UTF-8 is rejected, rather than replaced with
+// U+FFFD, so that different data never has the same canonical form.
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("invalid utf8: %q", data)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	buf := new(bytes.Buffer)
+	if err := canonicalizeJSONValue(dec, buf); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return buf.Bytes(), nil
+}
+
+func canonicalizeJSONValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			return canonicalizeJSONObject(dec, buf)
+		case '[':
+			return canonicalizeJSONArray(dec, buf)
+		default:
+			return fmt.Errorf("unexpected delimiter %v", tok)
+		}
+	case string:
+		return writeCanonicalJSONString(buf, tok)
+	case json.Number:
+		return writeCanonicalJSONNumber(buf, tok)
+	case bool:
+		buf.WriteString(strconv.FormatBool(tok))
+		return nil
+	case nil:
+		buf.WriteString("null")
+		return nil
+	default:
+		return fmt.Errorf("non-exhaustive pattern: token %T", tok)
+	}
+}
+
+func canonicalizeJSONObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	type member struct {
+		key   string
+		value []byte
+	}
+	members := []member{}
+	seen := map[string]struct{}{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", tok)
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate object key %q", key)
+		}
+		seen[key] = struct{}{}
+		value := new(bytes.Buffer)
+		if err := canonicalizeJSONValue(dec, value); err != nil {
+			return fmt.Errorf("canonicalizing %q: %v", key, err)
+		}
+		members = append(members, member{key: key, value: value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+	buf.WriteByte('{')
+	for i, member := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeCanonicalJSONString(buf, member.key); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		buf.Write(member.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func canonicalizeJSONArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := canonicalizeJSONValue(dec, buf); err != nil {
+			return fmt.Errorf("canonicalizing element %v: %v", i, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func writeCanonicalJSONString(buf *bytes.Buffer, str string) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(str); err != nil {
+		return err
+	}
+	// The encoder always terminates its output with a newline, which is not
+	// part of the canonical encoding.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+func writeCanonicalJSONNumber(buf *bytes.Buffer, num json.Number) error {
+	str := string(num)
+	if !strings.ContainsAny(str, ".eE") {
+		// The JSON grammar does not allow leading zeros, so integer literals
+		// are already canonical (except for negative zero).
+		if str == "-0" {
+			str = "0"
+		}
+		buf.WriteString(str)
+		return nil
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return fmt.Errorf("malformed number %v: %v", num, err)
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		buf.WriteString(strconv.FormatInt(int64(f), 10))
+		return nil
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	return nil
+}
@@ -0,0 +1,119 @@
+package tx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing/quick"
+
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canonical JSON", func() {
+
+	Context("when encoding a transaction canonically", func() {
+		It("should unmarshal to the same transaction", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := transaction.CanonicalJSON()
+				Expect(err).ToNot(HaveOccurred())
+				decoded, err := tx.UnmarshalTxJSON(data, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decoded.Hash).To(Equal(transaction.Hash))
+
+				hash, err := tx.NewTxHash(decoded.Version, decoded.Selector, decoded.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(transaction.Hash))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should be valid canonical json", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := transaction.CanonicalJSON()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tx.ValidateCanonicalJSON(data)).To(Succeed())
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when unmarshaling a non-canonical transaction", func() {
+		It("should only return an error in strict mode", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := json.MarshalIndent(transaction, "", "  ")
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = tx.UnmarshalTxJSON(data, true)
+				Expect(err).To(HaveOccurred())
+				decoded, err := tx.UnmarshalTxJSON(data, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decoded.Hash).To(Equal(transaction.Hash))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should reject values that are not canonically encoded", func() {
+			transaction, err := tx.NewTx("BTC/toEthereum", txutil.RandomTxInput(rand.New(rand.NewSource(0))))
+			Expect(err).ToNot(HaveOccurred())
+			data, err := transaction.CanonicalJSON()
+			Expect(err).ToNot(HaveOccurred())
+
+			// Leading zeros in numeric strings are accepted by the JSON
+			// unmarshaler, but are not canonical.
+			txindex, err := json.Marshal(transaction.Input.Get("txindex"))
+			Expect(err).ToNot(HaveOccurred())
+			nonCanonical := bytes.Replace(data, txindex, []byte(`"0`+string(txindex[1:])), 1)
+			Expect(nonCanonical).ToNot(Equal(data))
+
+			_, err = tx.UnmarshalTxJSON(nonCanonical, false)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = tx.UnmarshalTxJSON(nonCanonical, true)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when canonicalizing json", func() {
+		table := []struct {
+			input    string
+			expected string
+		}{
+			{`{ "b": 1, "a": [1, 2, {"d": null, "c": true}] }`, `{"a":[1,2,{"c":true,"d":null}],"b":1}`},
+			{`{"z": "<&>", "y": "é"}`, `{"y":"é","z":"<&>"}`},
+			{`[1.0, 1e2, -0, 0.5, 1.50, 1E400]`, ``},
+			{`[1.0, 1e2, -0, 0.5, 1.50]`, `[1,100,0,0.5,1.5]`},
+			{`{"a": 1, "a": 2}`, ``},
+			{`{"a": 1} {"b": 2}`, ``},
+			{`{"a": `, ``},
+			{"\"\xff\"", ``},
+			{"{\"a\xc3\": 1}", ``},
+			{"[\"\xed\xa0\x80\"]", ``},
+		}
+
+		for _, entry := range table {
+			entry := entry
+			It("should return the expected encoding", func() {
+				data, err := tx.CanonicalizeJSON([]byte(entry.input))
+				if entry.expected == "" {
+					Expect(err).To(HaveOccurred())
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal(entry.expected))
+				Expect(tx.ValidateCanonicalJSON(data)).To(Succeed())
+				if entry.input != entry.expected {
+					Expect(tx.ValidateCanonicalJSON([]byte(entry.input))).ToNot(Succeed())
+				}
+			})
+		}
+	})
+})
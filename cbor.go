@@ -0,0 +1,621 @@
+package tx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/renproject/id"
+	"github.com/renproject/pack"
+)
+
+// Transactions are encoded into CBOR (RFC 8949) using a compact, array-based
+// layout:
+//
+//	Tx         = [hash: bytes, version: text, selector: text, in: Typed, out: Typed]
+//	WithStatus = [tx: Tx, status: uint]
+//	Typed      = [type: Type, value: [field values...]]
+//	Type       = kind: uint                           ; scalar kinds
+//	           | [20, [[name: text, type: Type]...]]  ; struct
+//	           | [21, elem: Type]                     ; list
+//
+// Values are encoded without type information, because it is given by the
+// type. Booleans are encoded as CBOR booleans, integers as unsigned integers
+// (or tagged bignums when they do not fit into 64 bits), strings as text, byte
+// arrays as bytes, and structs and lists as arrays. Text must be valid UTF-8,
+// as required by RFC 8949, so versions, selectors, field names, and strings
+// that are not valid UTF-8 cannot be encoded or decoded.
+//
+// The encoding is deterministic: it only uses definite lengths and the
+// shortest possible argument encodings, and never uses maps. Decoding rejects
+// data that does not follow these rules, so every transaction has exactly one
+// valid CBOR encoding.
+
+const (
+	cborMajorUint   = 0
+	cborMajorBytes  = 2
+	cborMajorText   = 3
+	cborMajorArray  = 4
+	cborMajorTag    = 6
+	cborMajorSimple = 7
+
+	cborSimpleFalse = 20
+	cborSimpleTrue  = 21
+
+	cborTagBignum = 2
+)
+
+// MarshalCBOR implements the CBOR marshaler interface.
+func (tx Tx) MarshalCBOR() ([]byte, error) {
+	return appendCBORTx(nil, tx)
+}
+
+// UnmarshalCBOR implements the CBOR unmarshaler interface.
+func (tx *Tx) UnmarshalCBOR(data []byte) error {
+	dec := cborDecoder{data: data}
+	transaction, err := dec.tx()
+	if err != nil {
+		return err
+	}
+	if len(dec.data) != 0 {
+		return fmt.Errorf("unexpected %v bytes after tx", len(dec.data))
+	}
+	*tx = transaction
+	return nil
+}
+
+// MarshalCBOR implements the CBOR marshaler interface.
+func (w WithStatus) MarshalCBOR() ([]byte, error) {
+	buf := appendCBORHead(nil, cborMajorArray, 2)
+	buf, err := appendCBORTx(buf, w.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBORHead(buf, cborMajorUint, uint64(w.Status)), nil
+}
+
+// UnmarshalCBOR implements the CBOR unmarshaler interface.
+func (w *WithStatus) UnmarshalCBOR(data []byte) error {
+	dec := cborDecoder{data: data}
+	if err := dec.arrayOf(2); err != nil {
+		return err
+	}
+	transaction, err := dec.tx()
+	if err != nil {
+		return err
+	}
+	status, err := dec.uint()
+	if err != nil {
+		return fmt.Errorf("decoding status: %v", err)
+	}
+	if status > 0xFF {
+		return fmt.Errorf("status overflow: %v", status)
+	}
+	if len(dec.data) != 0 {
+		return fmt.Errorf("unexpected %v bytes after tx with status", len(dec.data))
+	}
+	w.Tx = transaction
+	w.Status = Status(status)
+	return nil
+}
+
+func appendCBORTx(buf []byte, tx Tx) ([]byte, error) {
+	var err error
+	buf = appendCBORHead(buf, cborMajorArray, 5)
+	buf = appendCBORBytes(buf, cborMajorBytes, tx.Hash[:])
+	if buf, err = appendCBORText(buf, string(tx.Version)); err != nil {
+		return nil, fmt.Errorf("encoding version: %v", err)
+	}
+	if buf, err = appendCBORText(buf, string(tx.Selector)); err != nil {
+		return nil, fmt.Errorf("encoding selector: %v", err)
+	}
+	if buf, err = appendCBORTyped(buf, tx.Input); err != nil {
+		return nil, fmt.Errorf("encoding input: %v", err)
+	}
+	if buf, err = appendCBORTyped(buf, tx.Output); err != nil {
+		return nil, fmt.Errorf("encoding output: %v", err)
+	}
+	return buf, nil
+}
+
+func appendCBORTyped(buf []byte, typed pack.Typed) ([]byte, error) {
+	def, err := NewTypeDef(typed.Type())
+	if err != nil {
+		return nil, err
+	}
+	buf = appendCBORHead(buf, cborMajorArray, 2)
+	if buf, err = appendCBORTypeDef(buf, def); err != nil {
+		return nil, err
+	}
+	return appendCBORValue(buf, pack.Struct(typed))
+}
+
+func appendCBORTypeDef(buf []byte, def TypeDef) ([]byte, error) {
+	var err error
+	switch def.Kind {
+	case pack.KindStruct:
+		buf = appendCBORHead(buf, cborMajorArray, 2)
+		buf = appendCBORHead(buf, cborMajorUint, uint64(def.Kind))
+		buf = appendCBORHead(buf, cborMajorArray, uint64(len(def.Fields)))
+		for _, field := range def.Fields {
+			buf = appendCBORHead(buf, cborMajorArray, 2)
+			if buf, err = appendCBORText(buf, field.Name); err != nil {
+				return nil, fmt.Errorf("encoding field name: %v", err)
+			}
+			if buf, err = appendCBORTypeDef(buf, field.Type); err != nil {
+				return nil, fmt.Errorf("encoding \"%v\": %v", field.Name, err)
+			}
+		}
+		return buf, nil
+	case pack.KindList:
+		buf = appendCBORHead(buf, cborMajorArray, 2)
+		buf = appendCBORHead(buf, cborMajorUint, uint64(def.Kind))
+		return appendCBORTypeDef(buf, *def.Elem)
+	default:
+		return appendCBORHead(buf, cborMajorUint, uint64(def.Kind)), nil
+	}
+}
+
+func appendCBORValue(buf []byte, v pack.Value) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case pack.Bool:
+		if v {
+			return appendCBORHead(buf, cborMajorSimple, cborSimpleTrue), nil
+		}
+		return appendCBORHead(buf, cborMajorSimple, cborSimpleFalse), nil
+	case pack.U8:
+		return appendCBORHead(buf, cborMajorUint, uint64(v)), nil
+	case pack.U16:
+		return appendCBORHead(buf, cborMajorUint, uint64(v)), nil
+	case pack.U32:
+		return appendCBORHead(buf, cborMajorUint, uint64(v)), nil
+	case pack.U64:
+		return appendCBORHead(buf, cborMajorUint, uint64(v)), nil
+	case pack.U128:
+		return appendCBORBigUint(buf, v.Int()), nil
+	case pack.U256:
+		return appendCBORBigUint(buf, v.Int()), nil
+	case pack.String:
+		return appendCBORText(buf, string(v))
+	case pack.Bytes:
+		return appendCBORBytes(buf, cborMajorBytes, v), nil
+	case pack.Bytes32:
+		return appendCBORBytes(buf, cborMajorBytes, v[:]), nil
+	case pack.Bytes65:
+		return appendCBORBytes(buf, cborMajorBytes, v[:]), nil
+	case pack.Typed:
+		return appendCBORValue(buf, pack.Struct(v))
+	case pack.Struct:
+		buf = appendCBORHead(buf, cborMajorArray, uint64(len(v)))
+		for _, field := range v {
+			if buf, err = appendCBORValue(buf, field.Value); err != nil {
+				return nil, fmt.Errorf("encoding \"%v\": %v", field.Name, err)
+			}
+		}
+		return buf, nil
+	case pack.List:
+		buf = appendCBORHead(buf, cborMajorArray, uint64(len(v.Elems)))
+		for i, elem := range v.Elems {
+			if buf, err = appendCBORValue(buf, elem); err != nil {
+				return nil, fmt.Errorf("encoding list element %v: %v", i, err)
+			}
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("non-exhaustive pattern: value %T", v)
+	}
+}
+
+// appendCBORBigUint encodes an unsigned integer as a plain CBOR integer if it
+// fits into 64 bits, and as a tagged bignum otherwise. This is the preferred
+// serialization defined by RFC 8949.
+func appendCBORBigUint(buf []byte, x *big.Int) []byte {
+	if x.IsUint64() {
+		return appendCBORHead(buf, cborMajorUint, x.Uint64())
+	}
+	buf = appendCBORHead(buf, cborMajorTag, cborTagBignum)
+	return appendCBORBytes(buf, cborMajorBytes, x.Bytes())
+}
+
+// appendCBORText encodes a string as text. It returns an error if the string is
+// not valid UTF-8.
+func appendCBORText(buf []byte, str string) ([]byte, error) {
+	if !utf8.ValidString(str) {
+		return nil, fmt.Errorf("invalid utf8: %q", str)
+	}
+	buf = appendCBORHead(buf, cborMajorText, uint64(len(str)))
+	return append(buf, str...), nil
+}
+
+func appendCBORBytes(buf []byte, major byte, data []byte) []byte {
+	buf = appendCBORHead(buf, major, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// appendCBORHead appends the initial bytes of a CBOR data item, using the
+// shortest possible encoding of the argument.
+func appendCBORHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= 0xFF:
+		return append(buf, major|24, byte(arg))
+	case arg <= 0xFFFF:
+		buf = append(buf, major|25)
+		return append(buf, byte(arg>>8), byte(arg))
+	case arg <= 0xFFFFFFFF:
+		buf = append(buf, major|26)
+		return append(buf, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	default:
+		buf = append(buf, major|27)
+		b := [8]byte{}
+		binary.BigEndian.PutUint64(b[:], arg)
+		return append(buf, b[:]...)
+	}
+}
+
+// cborDecoder decodes the subset of CBOR that is produced by the marshaling
+// functions, and rejects everything else.
+type cborDecoder struct {
+	data []byte
+}
+
+func (dec *cborDecoder) tx() (Tx, error) {
+	if err := dec.arrayOf(5); err != nil {
+		return Tx{}, err
+	}
+	tx := Tx{}
+	hash, err := dec.bytes(cborMajorBytes)
+	if err != nil {
+		return Tx{}, fmt.Errorf("decoding hash: %v", err)
+	}
+	if len(hash) != id.SizeHintHash {
+		return Tx{}, fmt.Errorf("expected hash len=%v, got len=%v", id.SizeHintHash, len(hash))
+	}
+	copy(tx.Hash[:], hash)
+	version, err := dec.text()
+	if err != nil {
+		return Tx{}, fmt.Errorf("decoding version: %v", err)
+	}
+	tx.Version = Version(version)
+	selector, err := dec.text()
+	if err != nil {
+		return Tx{}, fmt.Errorf("decoding selector: %v", err)
+	}
+	tx.Selector = Selector(selector)
+	if tx.Input, err = dec.typed(); err != nil {
+		return Tx{}, fmt.Errorf("decoding input: %v", err)
+	}
+	if tx.Output, err = dec.typed(); err != nil {
+		return Tx{}, fmt.Errorf("decoding output: %v", err)
+	}
+	return tx, nil
+}
+
+func (dec *cborDecoder) typed() (pack.Typed, error) {
+	if err := dec.arrayOf(2); err != nil {
+		return nil, err
+	}
+	def, err := dec.typeDef(0)
+	if err != nil {
+		return nil, fmt.Errorf("decoding type: %v", err)
+	}
+	if def.Kind != pack.KindStruct {
+		return nil, fmt.Errorf("expected kind %v, got kind %v", pack.KindStruct, def.Kind)
+	}
+	v, err := dec.value(def)
+	if err != nil {
+		return nil, fmt.Errorf("decoding value: %v", err)
+	}
+	return pack.Typed(v.(pack.Struct)), nil
+}
+
+func (dec *cborDecoder) typeDef(depth int) (TypeDef, error) {
+	if depth > maxTypeDefDepth {
+		return TypeDef{}, fmt.Errorf("type nested too deeply: max depth=%v", maxTypeDefDepth)
+	}
+	if len(dec.data) > 0 && dec.data[0]>>5 == cborMajorUint {
+		kind, err := dec.uint()
+		if err != nil {
+			return TypeDef{}, err
+		}
+		if kind > 0xFF || !isScalarKind(pack.Kind(kind)) {
+			return TypeDef{}, fmt.Errorf("unexpected scalar kind %v", kind)
+		}
+		return TypeDef{Kind: pack.Kind(kind)}, nil
+	}
+
+	if err := dec.arrayOf(2); err != nil {
+		return TypeDef{}, err
+	}
+	kind, err := dec.uint()
+	if err != nil {
+		return TypeDef{}, err
+	}
+	switch pack.Kind(kind) {
+	case pack.KindStruct:
+		n, err := dec.array()
+		if err != nil {
+			return TypeDef{}, err
+		}
+		def := TypeDef{Kind: pack.KindStruct, Fields: make([]TypeDefField, n)}
+		for i := range def.Fields {
+			if err := dec.arrayOf(2); err != nil {
+				return TypeDef{}, err
+			}
+			name, err := dec.text()
+			if err != nil {
+				return TypeDef{}, err
+			}
+			def.Fields[i].Name = string(name)
+			if def.Fields[i].Type, err = dec.typeDef(depth + 1); err != nil {
+				return TypeDef{}, fmt.Errorf("decoding \"%v\": %v", name, err)
+			}
+		}
+		return def, nil
+	case pack.KindList:
+		elem, err := dec.typeDef(depth + 1)
+		if err != nil {
+			return TypeDef{}, err
+		}
+		return TypeDef{Kind: pack.KindList, Elem: &elem}, nil
+	default:
+		return TypeDef{}, fmt.Errorf("unexpected abstract kind %v", kind)
+	}
+}
+
+func (dec *cborDecoder) value(def TypeDef) (pack.Value, error) {
+	switch def.Kind {
+	case pack.KindBool:
+		major, arg, err := dec.head()
+		if err != nil {
+			return nil, err
+		}
+		if major != cborMajorSimple || (arg != cborSimpleFalse && arg != cborSimpleTrue) {
+			return nil, fmt.Errorf("expected bool, got major type %v", major)
+		}
+		return pack.NewBool(arg == cborSimpleTrue), nil
+	case pack.KindU8:
+		x, err := dec.uintWithMax(0xFF)
+		return pack.NewU8(uint8(x)), err
+	case pack.KindU16:
+		x, err := dec.uintWithMax(0xFFFF)
+		return pack.NewU16(uint16(x)), err
+	case pack.KindU32:
+		x, err := dec.uintWithMax(0xFFFFFFFF)
+		return pack.NewU32(uint32(x)), err
+	case pack.KindU64:
+		x, err := dec.uint()
+		return pack.NewU64(x), err
+	case pack.KindU128:
+		b16 := [16]byte{}
+		if err := dec.bigUint(b16[:]); err != nil {
+			return nil, err
+		}
+		return pack.NewU128(b16), nil
+	case pack.KindU256:
+		b32 := [32]byte{}
+		if err := dec.bigUint(b32[:]); err != nil {
+			return nil, err
+		}
+		return pack.NewU256(b32), nil
+	case pack.KindString:
+		str, err := dec.text()
+		return pack.NewString(str), err
+	case pack.KindBytes:
+		data, err := dec.bytes(cborMajorBytes)
+		return pack.NewBytes(data), err
+	case pack.KindBytes32:
+		b32 := pack.Bytes32{}
+		data, err := dec.bytes(cborMajorBytes)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != len(b32) {
+			return nil, fmt.Errorf("expected bytes32 len=%v, got len=%v", len(b32), len(data))
+		}
+		copy(b32[:], data)
+		return b32, nil
+	case pack.KindBytes65:
+		b65 := pack.Bytes65{}
+		data, err := dec.bytes(cborMajorBytes)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != len(b65) {
+			return nil, fmt.Errorf("expected bytes65 len=%v, got len=%v", len(b65), len(data))
+		}
+		copy(b65[:], data)
+		return b65, nil
+	case pack.KindStruct:
+		if err := dec.arrayOf(len(def.Fields)); err != nil {
+			return nil, err
+		}
+		s := make(pack.Struct, len(def.Fields))
+		for i, field := range def.Fields {
+			v, err := dec.value(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("decoding \"%v\": %v", field.Name, err)
+			}
+			s[i] = pack.NewStructField(field.Name, v)
+		}
+		return s, nil
+	case pack.KindList:
+		t, err := def.Elem.PackType()
+		if err != nil {
+			return nil, err
+		}
+		n, err := dec.array()
+		if err != nil {
+			return nil, err
+		}
+		list := pack.List{T: t, Elems: make([]pack.Value, n)}
+		for i := range list.Elems {
+			if list.Elems[i], err = dec.value(*def.Elem); err != nil {
+				return nil, fmt.Errorf("decoding list element %v: %v", i, err)
+			}
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("non-exhaustive pattern: kind %v", def.Kind)
+	}
+}
+
+// head decodes the initial bytes of a data item, and rejects arguments that
+// are not encoded in the shortest possible way.
+func (dec *cborDecoder) head() (byte, uint64, error) {
+	if len(dec.data) < 1 {
+		return 0, 0, fmt.Errorf("unexpected end of data")
+	}
+	major, info := dec.data[0]>>5, dec.data[0]&0x1F
+	dec.data = dec.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	var n int
+	var min uint64
+	switch info {
+	case 24:
+		n, min = 1, 24
+	case 25:
+		n, min = 2, 0x100
+	case 26:
+		n, min = 4, 0x10000
+	case 27:
+		n, min = 8, 0x100000000
+	default:
+		return 0, 0, fmt.Errorf("unsupported additional information %v", info)
+	}
+	if len(dec.data) < n {
+		return 0, 0, fmt.Errorf("unexpected end of data")
+	}
+	arg := uint64(0)
+	for _, b := range dec.data[:n] {
+		arg = arg<<8 | uint64(b)
+	}
+	dec.data = dec.data[n:]
+	if arg < min || (major == cborMajorSimple) {
+		return 0, 0, fmt.Errorf("non-canonical argument %v", arg)
+	}
+	return major, arg, nil
+}
+
+func (dec *cborDecoder) expect(major byte) (uint64, error) {
+	got, arg, err := dec.head()
+	if err != nil {
+		return 0, err
+	}
+	if got != major {
+		return 0, fmt.Errorf("expected major type %v, got major type %v", major, got)
+	}
+	return arg, nil
+}
+
+func (dec *cborDecoder) uint() (uint64, error) {
+	return dec.expect(cborMajorUint)
+}
+
+func (dec *cborDecoder) uintWithMax(max uint64) (uint64, error) {
+	x, err := dec.uint()
+	if err != nil {
+		return 0, err
+	}
+	if x > max {
+		return 0, fmt.Errorf("overflow: %v", x)
+	}
+	return x, nil
+}
+
+// bigUint decodes an unsigned integer, or a tagged bignum, into a big-endian
+// byte slice. Bignums that fit into 64 bits, or that have leading zeros, are
+// rejected.
+func (dec *cborDecoder) bigUint(dst []byte) error {
+	if len(dec.data) > 0 && dec.data[0]>>5 == cborMajorUint {
+		x, err := dec.uint()
+		if err != nil {
+			return err
+		}
+		b := [8]byte{}
+		binary.BigEndian.PutUint64(b[:], x)
+		if len(dst) < len(b) {
+			return fmt.Errorf("overflow: %v", x)
+		}
+		copy(dst[len(dst)-len(b):], b[:])
+		return nil
+	}
+	tag, err := dec.expect(cborMajorTag)
+	if err != nil {
+		return err
+	}
+	if tag != cborTagBignum {
+		return fmt.Errorf("expected tag %v, got tag %v", cborTagBignum, tag)
+	}
+	data, err := dec.bytes(cborMajorBytes)
+	if err != nil {
+		return err
+	}
+	if len(data) <= 8 || data[0] == 0 {
+		return fmt.Errorf("non-canonical bignum")
+	}
+	if len(data) > len(dst) {
+		return fmt.Errorf("overflow: expected len<=%v, got len=%v", len(dst), len(data))
+	}
+	copy(dst[len(dst)-len(data):], data)
+	return nil
+}
+
+// bytes decodes a byte string or a text string, depending on the major type.
+func (dec *cborDecoder) bytes(major byte) ([]byte, error) {
+	n, err := dec.expect(major)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(dec.data)) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	data := make([]byte, n)
+	copy(data, dec.data)
+	dec.data = dec.data[n:]
+	return data, nil
+}
+
+// text decodes a text string. It returns an error if the string is not valid
+// UTF-8.
+func (dec *cborDecoder) text() (string, error) {
+	data, err := dec.bytes(cborMajorText)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("invalid utf8: %q", data)
+	}
+	return string(data), nil
+}
+
+// array decodes the head of an array, and returns its length. The length is
+// checked against the remaining data (every element needs at least one byte)
+// so that it is safe to allocate.
+func (dec *cborDecoder) array() (int, error) {
+	n, err := dec.expect(cborMajorArray)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(dec.data)) {
+		return 0, fmt.Errorf("unexpected end of data")
+	}
+	return int(n), nil
+}
+
+func (dec *cborDecoder) arrayOf(n int) error {
+	m, err := dec.array()
+	if err != nil {
+		return err
+	}
+	if m != n {
+		return fmt.Errorf("expected array len=%v, got len=%v", n, m)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package tx_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing/quick"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CBOR", func() {
+
+	Context("when marshaling and then unmarshaling good transactions", func() {
+		It("should preserve the transaction hash", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := transaction.MarshalCBOR()
+				Expect(err).ToNot(HaveOccurred())
+				other := tx.Tx{}
+				Expect(other.UnmarshalCBOR(data)).To(Succeed())
+
+				hash, err := tx.NewTxHash(other.Version, other.Selector, other.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(transaction.Hash))
+				Expect(other.Hash).To(Equal(transaction.Hash))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when marshaling nested structs and lists", func() {
+		It("should preserve their types exactly", func() {
+			list, err := pack.NewList(pack.NewU128FromUint64(1), pack.NewU128(
+				[16]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			))
+			Expect(err).ToNot(HaveOccurred())
+			transaction, err := tx.NewTx("BTC/toEthereum", pack.NewTyped(
+				"list", list,
+				"empty", pack.EmptyList(pack.NewStruct("x", pack.Bytes65{}).Type()),
+				"inner", pack.NewStruct("bool", pack.NewBool(false), "u16", pack.NewU16(300)),
+			))
+			Expect(err).ToNot(HaveOccurred())
+			data, err := transaction.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			other := tx.Tx{}
+			Expect(other.UnmarshalCBOR(data)).To(Succeed())
+
+			expected, err := surge.ToBinary(transaction)
+			Expect(err).ToNot(HaveOccurred())
+			got, err := surge.ToBinary(other)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(got).To(Equal(expected))
+		})
+	})
+
+	Context("when unmarshaling non-canonical data", func() {
+		transaction, _ := tx.NewTx("BTC/toEthereum", pack.NewTyped("x", pack.NewU64(1)))
+		data, _ := transaction.MarshalCBOR()
+
+		It("should reject trailing bytes", func() {
+			other := tx.Tx{}
+			Expect(other.UnmarshalCBOR(append(data, 0x00))).ToNot(Succeed())
+		})
+
+		It("should reject non-minimal arguments", func() {
+			// The first byte is the head of an array with 5 elements. Encode
+			// the same array length using an extra byte.
+			nonMinimal := append([]byte{0x98, 0x05}, data[1:]...)
+			other := tx.Tx{}
+			Expect(other.UnmarshalCBOR(nonMinimal)).ToNot(Succeed())
+		})
+
+		It("should reject truncated data", func() {
+			for i := range data {
+				other := tx.Tx{}
+				Expect(other.UnmarshalCBOR(data[:i])).ToNot(Succeed())
+			}
+		})
+	})
+
+	Context("when handling text that is not valid UTF-8", func() {
+		invalid := "\xff\xfe"
+
+		It("should return an error when marshaling", func() {
+			transactions := []tx.Tx{
+				{Version: tx.Version(invalid), Selector: "BTC/toEthereum"},
+				{Version: tx.Version1, Selector: tx.Selector(invalid)},
+				{Version: tx.Version1, Selector: "BTC/toEthereum", Input: pack.NewTyped(invalid, pack.NewU64(1))},
+				{Version: tx.Version1, Selector: "BTC/toEthereum", Input: pack.NewTyped("x", pack.String(invalid))},
+				{Version: tx.Version1, Selector: "BTC/toEthereum", Output: pack.NewTyped("x", pack.NewStruct(invalid, pack.NewU64(1)))},
+			}
+			for _, transaction := range transactions {
+				_, err := transaction.MarshalCBOR()
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should return an error when unmarshaling", func() {
+			transaction, err := tx.NewTx("BTC/toEthereum", pack.NewTyped("name", pack.String("value")))
+			Expect(err).ToNot(HaveOccurred())
+			data, err := transaction.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			for _, text := range []string{string(tx.Version1), "BTC/toEthereum", "name", "value"} {
+				// Find the text, including its head (all of the texts are
+				// short enough for the length to fit into the head).
+				corrupted := append([]byte{}, data...)
+				i := bytes.Index(corrupted, append([]byte{0x60 | byte(len(text))}, text...))
+				Expect(i).To(BeNumerically(">=", 0), text)
+				corrupted[i+1] = 0xFF
+				other := tx.Tx{}
+				Expect(other.UnmarshalCBOR(corrupted)).ToNot(Succeed(), text)
+			}
+		})
+	})
+})
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+)
+
+func runHash(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("hash", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	transaction, err := readTx(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+	if err != nil {
+		return fmt.Errorf("hashing: %v", err)
+	}
+	_, err = fmt.Fprintln(stdout, hash)
+	return err
+}
+
+func runVerify(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	transaction, err := readTx(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	if err := transaction.VerifyHash(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, "ok")
+	return err
+}
+
+func runDecode(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("decode", flag.ContinueOnError)
+	format := flags.String("format", "hex", "encoding of the input (hex or base64, which also accepts unpadded and URL-safe base64)")
+	withStatus := flags.Bool("status", false, "decode a transaction with its status")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	data, err = decodeText(*format, string(bytes.TrimSpace(data)))
+	if err != nil {
+		return err
+	}
+
+	var v interface{} = new(tx.Tx)
+	if *withStatus {
+		v = new(tx.WithStatus)
+	}
+	if err := surge.FromBinary(v, data); err != nil {
+		return fmt.Errorf("unmarshaling binary: %v", err)
+	}
+	return writeJSON(stdout, v)
+}
+
+func runEncode(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("encode", flag.ContinueOnError)
+	format := flags.String("format", "hex", "encoding of the output (hex or base64, which is standard padded base64)")
+	withStatus := flags.Bool("status", false, "encode a transaction with its status")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	var v interface{} = new(tx.Tx)
+	if *withStatus {
+		v = new(tx.WithStatus)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unmarshaling json: %v", err)
+	}
+	data, err = surge.ToBinary(v)
+	if err != nil {
+		return fmt.Errorf("marshaling binary: %v", err)
+	}
+	text, err := encodeText(*format, data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, text)
+	return err
+}
+
+func runSelector(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "inspect" {
+		return fmt.Errorf("expected \"selector inspect <selector>\"")
+	}
+	flags := flag.NewFlagSet("selector inspect", flag.ContinueOnError)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var selector tx.Selector
+	if flags.NArg() > 0 && flags.Arg(0) != "-" {
+		selector = tx.Selector(flags.Arg(0))
+	} else {
+		data, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %v", err)
+		}
+		selector = tx.Selector(strings.TrimSpace(string(data)))
+	}
+
+	return writeJSON(stdout, struct {
+		Selector    tx.Selector `json:"selector"`
+		Asset       string      `json:"asset"`
+		Source      string      `json:"source"`
+		Destination string      `json:"destination"`
+		Kind        string      `json:"kind"`
+	}{
+		Selector:    selector,
+		Asset:       string(selector.Asset()),
+		Source:      string(selector.Source()),
+		Destination: string(selector.Destination()),
+		Kind:        selectorKind(selector),
+	})
+}
+
+func runGen(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	n := flags.Int("n", 1, "number of transactions to generate")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed for the random number generator")
+	bad := flags.Bool("bad", false, "generate invalid transactions")
+	withStatus := flags.Bool("status", false, "generate transactions with random statuses")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := rand.New(rand.NewSource(*seed))
+	enc := json.NewEncoder(stdout)
+	for i := 0; i < *n; i++ {
+		transaction := txutil.RandomGoodTx(r)
+		if *bad {
+			transaction = txutil.RandomBadTx(r)
+		}
+		var v interface{} = transaction
+		if *withStatus {
+			v = tx.WithStatus{Tx: transaction, Status: txutil.RandomTxStatus(r)}
+		}
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("marshaling json: %v", err)
+		}
+	}
+	return nil
+}
+
+func runVectors(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("vectors", flag.ContinueOnError)
+	out := flags.String("o", "", "file to write the test vectors to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return txutil.WriteTestVectors(stdout)
+	}
+	buf := new(bytes.Buffer)
+	if err := txutil.WriteTestVectors(buf); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %v: %v", *out, err)
+	}
+	return nil
+}
+
+// selectorKind returns a human-readable description of the kind of transaction
+// that a selector represents.
+func selectorKind(selector tx.Selector) string {
+	switch {
+	case selector.IsIntrinsic():
+		return "intrinsic"
+	case selector.IsReturnStateAndOutputs():
+		return "returnStateAndOutputs"
+	case selector.IsClaimFeesFromEvent():
+		return "claimFeesFromEvent"
+	case selector.IsClaimFees():
+		return "claimFees"
+	case selector.IsLock() && selector.IsMint():
+		return "lockAndMint"
+	case selector.IsBurn() && selector.IsRelease():
+		return "burnAndRelease"
+	case selector.IsBurn() && selector.IsMint():
+		return "burnAndMint"
+	default:
+		return "unknown"
+	}
+}
+
+// readInput reads all data from the file named by the first argument, or from
+// stdin if there are no arguments or the first argument is "-".
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %v", err)
+		}
+		return data, nil
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("reading %v: %v", args[0], err)
+	}
+	return data, nil
+}
+
+func readTx(args []string, stdin io.Reader) (tx.Tx, error) {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return tx.Tx{}, err
+	}
+	transaction := tx.Tx{}
+	if err := json.Unmarshal(data, &transaction); err != nil {
+		return tx.Tx{}, fmt.Errorf("unmarshaling json: %v", err)
+	}
+	return transaction, nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling json: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func decodeText(format, text string) ([]byte, error) {
+	switch format {
+	case "hex":
+		data, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("decoding hex: %v", err)
+		}
+		return data, nil
+	case "base64":
+		// Accept both padded and unpadded, standard and URL, encodings.
+		text = strings.TrimRight(text, "=")
+		text = strings.NewReplacer("-", "+", "_", "/").Replace(text)
+		data, err := base64.RawStdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("decoding base64: %v", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func encodeText(format string, data []byte) (string, error) {
+	switch format {
+	case "hex":
+		return hex.EncodeToString(data), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
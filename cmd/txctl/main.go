@@ -0,0 +1,70 @@
+// Command txctl is a small utility for inspecting RenVM transactions. It can
+// compute and verify transaction hashes, convert transactions between JSON and
+// their surge binary representation, inspect selectors, and generate random
+// transactions and test vectors for testing.
+//
+// Inputs are read from the file named by the first argument after the flags, or
+// from stdin when no file is given (or when the file is "-").
+//
+//	txctl hash tx.json
+//	txctl verify < tx.json
+//	txctl decode -format base64 tx.bin
+//	txctl encode -status tx.json
+//	txctl selector inspect BTC/toEthereum
+//	txctl gen -n 10 -seed 42
+//	txctl vectors -o testdata/vectors.json
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "txctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run the command given by the arguments. It is separate from main so that it
+// can be driven with buffers in tests.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected command\n%v", usage)
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "hash":
+		return runHash(args, stdin, stdout)
+	case "verify":
+		return runVerify(args, stdin, stdout)
+	case "decode":
+		return runDecode(args, stdin, stdout)
+	case "encode":
+		return runEncode(args, stdin, stdout)
+	case "selector":
+		return runSelector(args, stdin, stdout)
+	case "gen":
+		return runGen(args, stdin, stdout)
+	case "vectors":
+		return runVectors(args, stdin, stdout)
+	case "help", "-h", "-help", "--help":
+		_, err := fmt.Fprintln(stdout, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n%v", cmd, usage)
+	}
+}
+
+const usage = `usage: txctl <command> [flags] [file]
+
+commands:
+  hash               print the hash of a JSON transaction
+  verify             check that the hash of a JSON transaction is correct
+  decode             convert a surge encoded transaction to JSON
+  encode             convert a JSON transaction to its surge encoding
+  selector inspect   print the asset, source, destination and kind of a selector
+  gen                generate random transactions as JSON
+  vectors            generate the golden test vectors for transaction hashing`
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing/quick"
+
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Txctl", func() {
+
+	exec := func(stdin string, args ...string) (string, error) {
+		stdout := new(bytes.Buffer)
+		err := run(args, strings.NewReader(stdin), stdout)
+		return strings.TrimSpace(stdout.String()), err
+	}
+
+	Context("when hashing a transaction", func() {
+		It("should print the transaction hash", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := json.Marshal(transaction)
+				Expect(err).ToNot(HaveOccurred())
+				out, err := exec(string(data), "hash")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(transaction.Hash.String()))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when verifying a transaction", func() {
+		It("should accept good transactions and reject bad ones", func() {
+			f := func(seed int64) bool {
+				r := rand.New(rand.NewSource(seed))
+				good, err := json.Marshal(txutil.RandomGoodTx(r))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = exec(string(good), "verify")
+				Expect(err).ToNot(HaveOccurred())
+
+				badTx := txutil.RandomBadTx(r)
+				bad, err := json.Marshal(badTx)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = exec(string(bad), "verify")
+				Expect(errors.Is(err, tx.ErrInvalidHash)).To(BeTrue())
+				Expect(err).To(MatchError(badTx.VerifyHash().Error()))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when encoding and then decoding a transaction", func() {
+		It("should return itself", func() {
+			f := func(seed int64) bool {
+				r := rand.New(rand.NewSource(seed))
+				for _, format := range []string{"hex", "base64"} {
+					transaction := txutil.RandomGoodTxWithStatus(r)
+					data, err := json.Marshal(transaction)
+					Expect(err).ToNot(HaveOccurred())
+					encoded, err := exec(string(data), "encode", "-status", "-format", format)
+					Expect(err).ToNot(HaveOccurred())
+					decoded, err := exec(encoded, "decode", "-status", "-format", format)
+					Expect(err).ToNot(HaveOccurred())
+
+					other := tx.WithStatus{}
+					Expect(json.Unmarshal([]byte(decoded), &other)).To(Succeed())
+					Expect(other.Hash).To(Equal(transaction.Hash))
+					Expect(other.Status).To(Equal(transaction.Status))
+					Expect(other.Input.String()).To(Equal(transaction.Input.String()))
+				}
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when encoding a transaction as base64", func() {
+		It("should use standard padded base64, and decode any base64", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				data, err := json.Marshal(transaction)
+				Expect(err).ToNot(HaveOccurred())
+				encodedHex, err := exec(string(data), "encode")
+				Expect(err).ToNot(HaveOccurred())
+				binary, err := hex.DecodeString(encodedHex)
+				Expect(err).ToNot(HaveOccurred())
+				encoded, err := exec(string(data), "encode", "-format", "base64")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(encoded).To(Equal(base64.StdEncoding.EncodeToString(binary)))
+
+				for _, text := range []string{encoded, base64.RawStdEncoding.EncodeToString(binary), base64.URLEncoding.EncodeToString(binary), base64.RawURLEncoding.EncodeToString(binary)} {
+					decoded, err := exec(text, "decode", "-format", "base64")
+					Expect(err).ToNot(HaveOccurred())
+					other := tx.Tx{}
+					Expect(json.Unmarshal([]byte(decoded), &other)).To(Succeed())
+					Expect(other.Hash).To(Equal(transaction.Hash))
+				}
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when inspecting a selector", func() {
+		table := []struct {
+			selector    string
+			source      string
+			destination string
+			kind        string
+		}{
+			{"BTC/toEthereum", "Bitcoin", "Ethereum", "lockAndMint"},
+			{"BTC/fromEthereum", "Ethereum", "Bitcoin", "burnAndRelease"},
+			{"BTC/toSolanaFromEthereum", "Ethereum", "Solana", "burnAndMint"},
+			{"BTC/epoch", "", "", "intrinsic"},
+			{"invalid", "", "", "unknown"},
+		}
+
+		for _, entry := range table {
+			entry := entry
+			It("should print its components", func() {
+				out, err := exec("", "selector", "inspect", entry.selector)
+				Expect(err).ToNot(HaveOccurred())
+				inspected := map[string]string{}
+				Expect(json.Unmarshal([]byte(out), &inspected)).To(Succeed())
+				Expect(inspected["source"]).To(Equal(entry.source))
+				Expect(inspected["destination"]).To(Equal(entry.destination))
+				Expect(inspected["kind"]).To(Equal(entry.kind))
+			})
+		}
+	})
+
+	Context("when generating transactions", func() {
+		It("should generate valid transactions deterministically", func() {
+			fst, err := exec("", "gen", "-n", "5", "-seed", "42")
+			Expect(err).ToNot(HaveOccurred())
+			snd, err := exec("", "gen", "-n", "5", "-seed", "42")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fst).To(Equal(snd))
+
+			lines := strings.Split(fst, "\n")
+			Expect(lines).To(HaveLen(5))
+			for _, line := range lines {
+				_, err := exec(line, "verify")
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	})
+
+	Context("when generating test vectors", func() {
+		It("should generate vectors that pass their own checks", func() {
+			out, err := exec("", "vectors")
+			Expect(err).ToNot(HaveOccurred())
+			vectors, err := txutil.ReadTestVectors(strings.NewReader(out))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(vectors)).To(BeNumerically(">", len(txutil.AllSelectors())))
+			for _, vector := range vectors {
+				Expect(vector.Check()).To(Succeed())
+			}
+		})
+	})
+
+	Context("when running an unknown command", func() {
+		It("should return an error", func() {
+			_, err := exec("", "unknown")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,199 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// Clone returns a deep copy of the transaction. Modifying the inputs or outputs
+// of the copy (for example, the bytes of the payload) does not modify the
+// original transaction. Nil inputs, outputs, and bytes stay nil.
+func (tx Tx) Clone() Tx {
+	return Tx{
+		Hash:     tx.Hash,
+		Version:  tx.Version,
+		Selector: tx.Selector,
+		Input:    cloneTyped(tx.Input),
+		Output:   cloneTyped(tx.Output),
+	}
+}
+
+// Equal returns true if the transaction is semantically equal to another
+// transaction. Inputs and outputs are compared by their binary encodings, so
+// nil and empty inputs (or outputs) are equal, and zero-value integers are
+// equal to zero. The order of fields is significant, because it changes the
+// hash of the transaction. Transactions that cannot be encoded are never
+// equal.
+func (tx Tx) Equal(other Tx) bool {
+	if tx.Hash != other.Hash || tx.Version != other.Version || tx.Selector != other.Selector {
+		return false
+	}
+	return equalEncodings(tx.Input, other.Input) && equalEncodings(tx.Output, other.Output)
+}
+
+// A TxDiff is a field that is different in two transactions.
+type TxDiff struct {
+	// Path of the field. It is "hash", "version", or "selector", or a path into
+	// the input or output, such as "in.amount", "out.revert", "in.a.b" for a
+	// nested struct field, or "in.list[2]" for a list element.
+	Path string
+	// A is the value of the field in the first transaction, and B is the value
+	// in the second transaction. They are nil if the field is missing. When
+	// the fields of a struct are the same, but in a different order, the diff
+	// is at the path of the struct, and A and B are the structs.
+	A, B pack.Value
+}
+
+// String returns a readable representation of the diff, such as
+// `in.amount: u256("1") != u256("2")`.
+func (diff TxDiff) String() string {
+	return fmt.Sprintf("%v: %v != %v", diff.Path, diffValueString(diff.A), diffValueString(diff.B))
+}
+
+func diffValueString(value pack.Value) string {
+	if value == nil {
+		return "<missing>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v(<error: %v>)", value.Type().Kind(), err)
+	}
+	return fmt.Sprintf("%v(%s)", value.Type().Kind(), data)
+}
+
+// Diff returns the fields that are different in the transaction and another
+// transaction, or nil if they are equal (see Equal). Inputs and outputs are
+// compared field by field, recursing into nested structs and lists, so that
+// the diffs point at the values that are different. Fields of the first
+// transaction come first, in order, followed by fields that are only in the
+// second transaction.
+func (tx Tx) Diff(other Tx) []TxDiff {
+	var diffs []TxDiff
+	if tx.Hash != other.Hash {
+		diffs = append(diffs, TxDiff{Path: "hash", A: pack.Bytes32(tx.Hash), B: pack.Bytes32(other.Hash)})
+	}
+	if tx.Version != other.Version {
+		diffs = append(diffs, TxDiff{Path: "version", A: pack.String(tx.Version), B: pack.String(other.Version)})
+	}
+	if tx.Selector != other.Selector {
+		diffs = append(diffs, TxDiff{Path: "selector", A: pack.String(tx.Selector), B: pack.String(other.Selector)})
+	}
+	diffs = appendStructDiffs(diffs, "in", pack.Struct(tx.Input), pack.Struct(other.Input))
+	diffs = appendStructDiffs(diffs, "out", pack.Struct(tx.Output), pack.Struct(other.Output))
+	return diffs
+}
+
+func appendStructDiffs(diffs []TxDiff, path string, a, b pack.Struct) []TxDiff {
+	n := len(diffs)
+	for _, field := range a {
+		diffs = appendValueDiffs(diffs, path+"."+field.Name, field.Value, b.Get(field.Name))
+	}
+	for _, field := range b {
+		if a.Get(field.Name) == nil {
+			diffs = append(diffs, TxDiff{Path: path + "." + field.Name, B: field.Value})
+		}
+	}
+	if len(diffs) == n && len(a) == len(b) {
+		for i := range a {
+			if a[i].Name != b[i].Name {
+				return append(diffs, TxDiff{Path: path, A: a, B: b})
+			}
+		}
+	}
+	return diffs
+}
+
+func appendValueDiffs(diffs []TxDiff, path string, a, b pack.Value) []TxDiff {
+	if a == nil || b == nil {
+		if a != nil || b != nil {
+			diffs = append(diffs, TxDiff{Path: path, A: a, B: b})
+		}
+		return diffs
+	}
+	switch a := a.(type) {
+	case pack.Struct:
+		if b, ok := b.(pack.Struct); ok {
+			return appendStructDiffs(diffs, path, a, b)
+		}
+	case pack.List:
+		if b, ok := b.(pack.List); ok && equalTypes(a.T, b.T) {
+			for i := 0; i < len(a.Elems) || i < len(b.Elems); i++ {
+				var elemA, elemB pack.Value
+				if i < len(a.Elems) {
+					elemA = a.Elems[i]
+				}
+				if i < len(b.Elems) {
+					elemB = b.Elems[i]
+				}
+				diffs = appendValueDiffs(diffs, fmt.Sprintf("%v[%v]", path, i), elemA, elemB)
+			}
+			return diffs
+		}
+	}
+	if !equalTypes(a.Type(), b.Type()) || !equalEncodings(a, b) {
+		diffs = append(diffs, TxDiff{Path: path, A: a, B: b})
+	}
+	return diffs
+}
+
+// equalEncodings returns true if two values have the same binary encoding.
+func equalEncodings(a, b surge.Marshaler) bool {
+	dataA, err := surge.ToBinary(a)
+	if err != nil {
+		return false
+	}
+	dataB, err := surge.ToBinary(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(dataA, dataB)
+}
+
+func cloneTyped(typed pack.Typed) pack.Typed {
+	if typed == nil {
+		return nil
+	}
+	return pack.Typed(cloneStruct(pack.Struct(typed)))
+}
+
+func cloneStruct(v pack.Struct) pack.Struct {
+	if v == nil {
+		return nil
+	}
+	cloned := make(pack.Struct, len(v))
+	for i, field := range v {
+		cloned[i] = pack.NewStructField(field.Name, cloneValue(field.Value))
+	}
+	return cloned
+}
+
+// cloneValue returns a deep copy of a value. Integers, strings, and fixed size
+// byte arrays are immutable, so they are not copied.
+func cloneValue(value pack.Value) pack.Value {
+	switch v := value.(type) {
+	case pack.Bytes:
+		if v == nil {
+			return v
+		}
+		return pack.NewBytes(append([]byte{}, v...))
+	case pack.Struct:
+		return cloneStruct(v)
+	case pack.Typed:
+		return cloneTyped(v)
+	case pack.List:
+		if v.Elems == nil {
+			return v
+		}
+		elems := make([]pack.Value, len(v.Elems))
+		for i := range v.Elems {
+			elems[i] = cloneValue(v.Elems[i])
+		}
+		return pack.List{T: v.T, Elems: elems}
+	default:
+		return value
+	}
+}
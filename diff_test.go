@@ -0,0 +1,149 @@
+package tx_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transaction diffs", func() {
+
+	Context("when cloning transactions", func() {
+		It("should return a deep copy", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			clone := transaction.Clone()
+			Expect(reflect.DeepEqual(clone, transaction)).To(BeTrue())
+
+			txid := clone.Input.Get("txid").(pack.Bytes)
+			Expect(txid).ToNot(BeEmpty())
+			txid[0] ^= 1
+			Expect(clone.Equal(transaction)).To(BeFalse())
+			Expect(transaction.VerifyHash()).To(Succeed())
+		})
+
+		It("should copy nested structs and lists", func() {
+			transaction := tx.Tx{
+				Input: pack.NewTyped(
+					"nested", pack.NewStruct("b", pack.NewBytes([]byte{1})),
+					"list", pack.List{T: pack.Bytes{}.Type(), Elems: []pack.Value{pack.NewBytes([]byte{2})}},
+				),
+			}
+			clone := transaction.Clone()
+			clone.Input.Get("nested").(pack.Struct).Get("b").(pack.Bytes)[0] = 3
+			clone.Input.Get("list").(pack.List).Elems[0].(pack.Bytes)[0] = 4
+			Expect(transaction.Input.Get("nested").(pack.Struct).Get("b")).To(Equal(pack.NewBytes([]byte{1})))
+			Expect(transaction.Input.Get("list").(pack.List).Elems[0]).To(Equal(pack.NewBytes([]byte{2})))
+		})
+
+		It("should keep nil inputs and outputs nil", func() {
+			clone := tx.Tx{}.Clone()
+			Expect(clone.Input).To(BeNil())
+			Expect(clone.Output).To(BeNil())
+		})
+	})
+
+	Context("when comparing transactions", func() {
+		It("should treat nil and empty inputs and outputs as equal", func() {
+			a := tx.Tx{Selector: "BTC/toEthereum"}
+			b := tx.Tx{Selector: "BTC/toEthereum", Input: pack.Typed{}, Output: pack.Typed{}}
+			Expect(reflect.DeepEqual(a, b)).To(BeFalse())
+			Expect(a.Equal(b)).To(BeTrue())
+			Expect(a.Diff(b)).To(BeEmpty())
+		})
+
+		It("should treat zero-value integers as zero", func() {
+			a := tx.Tx{Input: pack.NewTyped("amount", pack.U256{})}
+			b := tx.Tx{Input: pack.NewTyped("amount", pack.NewU256FromU64(0))}
+			Expect(a.Equal(b)).To(BeTrue())
+			Expect(a.Diff(b)).To(BeEmpty())
+		})
+
+		It("should be equal to a clone", func() {
+			f := func(transaction tx.Tx) bool {
+				clone := transaction.Clone()
+				return transaction.Equal(clone) && len(transaction.Diff(clone)) == 0
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should only be equal when there are no diffs", func() {
+			f := func(a, b tx.Tx) bool {
+				// Only the inputs are different, so that they are compared.
+				b.Hash, b.Version, b.Selector, b.Output = a.Hash, a.Version, a.Selector, a.Output
+				return a.Equal(b) == (len(a.Diff(b)) == 0)
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when diffing transactions", func() {
+		It("should return the differing top-level fields", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			a := txutil.RandomValidTx(r)
+			b := a.Clone()
+			b.Version = "99"
+			b.Selector = "BTC/fromEthereum"
+			b.Hash[0] ^= 1
+			diffs := a.Diff(b)
+			Expect(diffs).To(HaveLen(3))
+			Expect(diffs[0].Path).To(Equal("hash"))
+			Expect(diffs[1]).To(Equal(tx.TxDiff{Path: "version", A: pack.String(a.Version), B: pack.String("99")}))
+			Expect(diffs[2]).To(Equal(tx.TxDiff{Path: "selector", A: pack.String(a.Selector), B: pack.String("BTC/fromEthereum")}))
+		})
+
+		It("should return the paths of differing inputs and outputs", func() {
+			a := tx.Tx{
+				Input: pack.NewTyped(
+					"amount", pack.NewU256FromU64(1),
+					"nested", pack.NewStruct("x", pack.NewU64(1), "y", pack.String("y")),
+					"list", pack.List{T: pack.U64(0).Type(), Elems: []pack.Value{pack.NewU64(1), pack.NewU64(2)}},
+					"removed", pack.NewBool(true),
+				),
+				Output: pack.NewTyped("revert", pack.String("")),
+			}
+			b := tx.Tx{
+				Input: pack.NewTyped(
+					"amount", pack.NewU256FromU64(2),
+					"nested", pack.NewStruct("x", pack.NewU64(1), "y", pack.String("z")),
+					"list", pack.List{T: pack.U64(0).Type(), Elems: []pack.Value{pack.NewU64(1), pack.NewU64(3), pack.NewU64(4)}},
+					"added", pack.NewBool(false),
+				),
+				Output: pack.NewTyped("revert", pack.U32(0)),
+			}
+			Expect(a.Equal(b)).To(BeFalse())
+			Expect(a.Diff(b)).To(Equal([]tx.TxDiff{
+				{Path: "in.amount", A: pack.NewU256FromU64(1), B: pack.NewU256FromU64(2)},
+				{Path: "in.nested.y", A: pack.String("y"), B: pack.String("z")},
+				{Path: "in.list[1]", A: pack.NewU64(2), B: pack.NewU64(3)},
+				{Path: "in.list[2]", B: pack.NewU64(4)},
+				{Path: "in.removed", A: pack.NewBool(true)},
+				{Path: "in.added", B: pack.NewBool(false)},
+				{Path: "out.revert", A: pack.String(""), B: pack.U32(0)},
+			}))
+		})
+
+		It("should return a diff when fields are in a different order", func() {
+			a := tx.Tx{Input: pack.NewTyped("x", pack.NewU64(1), "y", pack.NewU64(2))}
+			b := tx.Tx{Input: pack.NewTyped("y", pack.NewU64(2), "x", pack.NewU64(1))}
+			Expect(a.Equal(b)).To(BeFalse())
+			Expect(a.Diff(b)).To(Equal([]tx.TxDiff{{Path: "in", A: pack.Struct(a.Input), B: pack.Struct(b.Input)}}))
+		})
+
+		It("should be readable", func() {
+			a := tx.Tx{Input: pack.NewTyped("amount", pack.NewU256FromU64(1), "to", pack.String("0x1"))}
+			b := tx.Tx{Input: pack.NewTyped("amount", pack.NewU256FromU64(2))}
+			diffs := a.Diff(b)
+			Expect(diffs).To(HaveLen(2))
+			Expect(diffs[0].String()).To(Equal(`in.amount: u256("1") != u256("2")`))
+			Expect(diffs[1].String()).To(Equal(`in.to: string("0x1") != <missing>`))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+)
+
+// MaxFeeBps is the maximum fee, in basis points, that can be charged when
+// minting or burning. It is the whole amount.
+const MaxFeeBps = 10000
+
+// A FeeSchedule defines the fees for moving an asset between chains. It is the
+// state that is changed by the ChangeFeesFn intrinsic.
+type FeeSchedule struct {
+	// Asset to which the fees apply.
+	Asset multichain.Asset `json:"asset"`
+
+	// Chains defines the fees for each chain, including the origin chain of
+	// the asset. Chains that are not defined do not charge any fees.
+	Chains []ChainFees `json:"chains"`
+}
+
+// ChainFees are the fees charged when moving an asset from, minting to, and
+// burning from, a chain.
+type ChainFees struct {
+	Chain multichain.Chain `json:"chain"`
+	// UnderlyingFee is a fixed fee that covers the cost of transactions on the
+	// chain. It is charged when the asset is moved from the chain (locked on
+	// its origin chain, or burned on a host chain), and it is in the native
+	// units of the asset.
+	UnderlyingFee pack.U256 `json:"underlyingFee"`
+	// MintFee and BurnFee are in basis points of the amount being moved.
+	MintFee pack.U16 `json:"mintFee"`
+	BurnFee pack.U16 `json:"burnFee"`
+}
+
+// Validate returns an error if a fee is greater than MaxFeeBps, or if a host
+// chain is defined more than once.
+func (schedule FeeSchedule) Validate() error {
+	chains := map[multichain.Chain]bool{}
+	for _, fees := range schedule.Chains {
+		if chains[fees.Chain] {
+			return fmt.Errorf("duplicate chain %v", fees.Chain)
+		}
+		chains[fees.Chain] = true
+		if fees.MintFee > MaxFeeBps {
+			return fmt.Errorf("mint fee for %v is too large: expected<=%v, got=%v", fees.Chain, MaxFeeBps, fees.MintFee)
+		}
+		if fees.BurnFee > MaxFeeBps {
+			return fmt.Errorf("burn fee for %v is too large: expected<=%v, got=%v", fees.Chain, MaxFeeBps, fees.BurnFee)
+		}
+	}
+	return nil
+}
+
+// ChainFees returns the fees for a chain. If the chain is not defined, no fees
+// are charged.
+func (schedule FeeSchedule) ChainFees(chain multichain.Chain) ChainFees {
+	for _, fees := range schedule.Chains {
+		if fees.Chain == chain {
+			return fees
+		}
+	}
+	return ChainFees{Chain: chain}
+}
+
+// A FeeEstimate is the breakdown of the fees charged for a transaction. All
+// fees are in the denomination of the amount that is sent, and the net amount
+// is in the denomination of the destination chain.
+type FeeEstimate struct {
+	Amount        Amount `json:"amount"`
+	UnderlyingFee Amount `json:"underlyingFee"`
+	MintFee       Amount `json:"mintFee"`
+	BurnFee       Amount `json:"burnFee"`
+	TotalFee      Amount `json:"totalFee"`
+	Net           Amount `json:"net"`
+}
+
+// EstimateFees returns the fees that are charged when the amount is sent to the
+// selector, and the net amount that is received by the recipient. The amount
+// must be in the denomination of the source chain of the selector (see
+// Tx.Amount).
+//
+// Fees are computed in a fixed order, so that all parties agree on rounding:
+//  1. The underlying fee of the source chain is charged.
+//  2. The mint fee of the destination chain is charged when minting, and the
+//     burn fee of the source chain is charged when burning. Both are computed
+//     from the amount that remains after the underlying fee, and each is
+//     rounded down.
+//  3. The net amount is what remains after all fees, converted into the
+//     denomination of the destination chain.
+//
+// An error is returned if the fees are greater than the amount.
+func EstimateFees(selector Selector, amount Amount, schedule FeeSchedule) (FeeEstimate, error) {
+	asset, source, destination := selector.Asset(), selector.Source(), selector.Destination()
+	if asset == "" || source == "" || destination == "" {
+		return FeeEstimate{}, fmt.Errorf("unsupported selector %v", selector)
+	}
+	if amount.Asset != asset || schedule.Asset != asset {
+		return FeeEstimate{}, fmt.Errorf("expected asset %v, got amount in %v and fees for %v", asset, amount.Asset, schedule.Asset)
+	}
+	if amount.Chain != source {
+		return FeeEstimate{}, fmt.Errorf("expected amount on %v, got amount on %v", source, amount.Chain)
+	}
+	if err := schedule.Validate(); err != nil {
+		return FeeEstimate{}, fmt.Errorf("invalid fee schedule: %v", err)
+	}
+
+	zero := amount.Denomination.NewAmount(pack.NewU256FromUint64(0))
+	estimate := FeeEstimate{Amount: amount, UnderlyingFee: zero, MintFee: zero, BurnFee: zero}
+	// The underlying fee is in native units, and must be converted when the
+	// amount is wrapped.
+	native, err := NativeDenomination(asset)
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	if estimate.UnderlyingFee, err = native.NewAmount(schedule.ChainFees(source).UnderlyingFee).Convert(amount.Denomination); err != nil {
+		return FeeEstimate{}, fmt.Errorf("converting underlying fee: %v", err)
+	}
+	remaining, err := amount.Sub(estimate.UnderlyingFee)
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("underlying fee is greater than amount: %w", err)
+	}
+	if selector.IsMint() {
+		if estimate.MintFee, err = remaining.MulDiv(uint64(schedule.ChainFees(destination).MintFee), MaxFeeBps); err != nil {
+			return FeeEstimate{}, err
+		}
+	}
+	if selector.IsBurn() {
+		if estimate.BurnFee, err = remaining.MulDiv(uint64(schedule.ChainFees(source).BurnFee), MaxFeeBps); err != nil {
+			return FeeEstimate{}, err
+		}
+	}
+
+	if estimate.TotalFee, err = estimate.UnderlyingFee.Add(estimate.MintFee); err != nil {
+		return FeeEstimate{}, err
+	}
+	if estimate.TotalFee, err = estimate.TotalFee.Add(estimate.BurnFee); err != nil {
+		return FeeEstimate{}, err
+	}
+	net, err := amount.Sub(estimate.TotalFee)
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("fees are greater than amount: %w", err)
+	}
+	if destination == asset.OriginChain() {
+		estimate.Net, err = net.ToNative()
+	} else {
+		estimate.Net, err = net.ToWrapped(destination)
+	}
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("converting net amount: %v", err)
+	}
+	return estimate, nil
+}
@@ -0,0 +1,169 @@
+package tx_test
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/surge/surgeutil"
+	"github.com/renproject/tx"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fees", func() {
+
+	t := reflect.TypeOf(tx.FeeSchedule{})
+	numTrials := 100
+
+	schedule := tx.FeeSchedule{
+		Asset: multichain.BTC,
+		Chains: []tx.ChainFees{
+			{Chain: multichain.Bitcoin, UnderlyingFee: pack.NewU256FromUint64(10000)},
+			{Chain: multichain.Ethereum, UnderlyingFee: pack.NewU256FromUint64(10000), MintFee: 15, BurnFee: 10},
+			{Chain: multichain.Solana, UnderlyingFee: pack.NewU256FromUint64(20000), MintFee: 20, BurnFee: 5},
+		},
+	}
+
+	amountOn := func(str string, chain multichain.Chain) tx.Amount {
+		amount, err := tx.ParseAmount(str)
+		Expect(err).ToNot(HaveOccurred())
+		if chain != amount.Chain {
+			amount, err = amount.ToWrapped(chain)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		return amount
+	}
+
+	Context("when marshaling and then unmarshaling a fee schedule", func() {
+		It("should return itself", func() {
+			for trial := 0; trial < numTrials; trial++ {
+				Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+				Expect(JSONMarshalUnmarshalCheck(t)).To(Succeed())
+			}
+		})
+	})
+
+	Context("when fuzzing a fee schedule", func() {
+		It("should not panic", func() {
+			for trial := 0; trial < numTrials; trial++ {
+				Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+				Expect(func() { JSONFuzz(t) }).ToNot(Panic())
+			}
+		})
+	})
+
+	Context("when estimating fees for a lock-and-mint", func() {
+		It("should charge the underlying fee and the mint fee", func() {
+			estimate, err := tx.EstimateFees("BTC/toEthereum", amountOn("1 BTC", multichain.Bitcoin), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.UnderlyingFee.String()).To(Equal("0.0001 BTC"))
+			Expect(estimate.MintFee.Value).To(Equal(pack.NewU256FromUint64(149985)))
+			Expect(estimate.BurnFee.IsZero()).To(BeTrue())
+			Expect(estimate.TotalFee.Value).To(Equal(pack.NewU256FromUint64(159985)))
+			Expect(estimate.Net.Value).To(Equal(pack.NewU256FromUint64(99840015)))
+			Expect(estimate.Net.Chain).To(Equal(multichain.Ethereum))
+		})
+	})
+
+	Context("when estimating fees for a burn-and-release", func() {
+		It("should charge the burn fee and the underlying fee", func() {
+			estimate, err := tx.EstimateFees("BTC/fromEthereum", amountOn("1 BTC", multichain.Ethereum), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(10000)))
+			Expect(estimate.UnderlyingFee.Chain).To(Equal(multichain.Ethereum))
+			Expect(estimate.MintFee.IsZero()).To(BeTrue())
+			Expect(estimate.BurnFee.Value).To(Equal(pack.NewU256FromUint64(99990)))
+			Expect(estimate.Net.Value).To(Equal(pack.NewU256FromUint64(99890010)))
+			Expect(estimate.Net.IsNative()).To(BeTrue())
+		})
+	})
+
+	Context("when estimating fees for a burn-and-mint", func() {
+		It("should charge the underlying fee, the burn fee, and the mint fee", func() {
+			estimate, err := tx.EstimateFees("BTC/toSolanaFromEthereum", amountOn("1 BTC", multichain.Ethereum), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(10000)))
+			Expect(estimate.BurnFee.Value).To(Equal(pack.NewU256FromUint64(99990)))
+			Expect(estimate.MintFee.Value).To(Equal(pack.NewU256FromUint64(199980)))
+			Expect(estimate.Net.Value).To(Equal(pack.NewU256FromUint64(99690030)))
+			Expect(estimate.Net.Chain).To(Equal(multichain.Solana))
+		})
+	})
+
+	Context("when chains have different underlying fees", func() {
+		It("should charge the underlying fee of the source chain", func() {
+			fromEthereum, err := tx.EstimateFees("BTC/fromEthereum", amountOn("1 BTC", multichain.Ethereum), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			fromSolana, err := tx.EstimateFees("BTC/fromSolana", amountOn("1 BTC", multichain.Solana), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fromEthereum.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(10000)))
+			Expect(fromSolana.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(20000)))
+			Expect(fromSolana.Net.Value).To(Equal(pack.NewU256FromUint64(99930010)))
+
+			toSolana, err := tx.EstimateFees("BTC/toSolanaFromEthereum", amountOn("1 BTC", multichain.Ethereum), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			toEthereum, err := tx.EstimateFees("BTC/toEthereumFromSolana", amountOn("1 BTC", multichain.Solana), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(toSolana.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(10000)))
+			Expect(toEthereum.UnderlyingFee.Value).To(Equal(pack.NewU256FromUint64(20000)))
+		})
+
+		It("should not charge an underlying fee for chains that are not defined", func() {
+			estimate, err := tx.EstimateFees("BTC/fromPolygon", amountOn("1 BTC", multichain.Polygon), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.UnderlyingFee.IsZero()).To(BeTrue())
+			Expect(estimate.TotalFee.IsZero()).To(BeTrue())
+		})
+	})
+
+	Context("when the fees do not divide evenly", func() {
+		It("should round each fee down", func() {
+			schedule := tx.FeeSchedule{
+				Asset: multichain.BTC,
+				Chains: []tx.ChainFees{
+					{Chain: multichain.Ethereum, MintFee: 15, BurnFee: 10},
+					{Chain: multichain.Solana, MintFee: 20, BurnFee: 5},
+				},
+			}
+			estimate, err := tx.EstimateFees("BTC/toSolanaFromEthereum", amountOn("0.00000999 BTC", multichain.Ethereum), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.BurnFee.Value).To(Equal(pack.NewU256FromUint64(0)))
+			Expect(estimate.MintFee.Value).To(Equal(pack.NewU256FromUint64(1)))
+			Expect(estimate.Net.Value).To(Equal(pack.NewU256FromUint64(998)))
+		})
+	})
+
+	Context("when a host chain has no fees", func() {
+		It("should not charge a mint fee", func() {
+			estimate, err := tx.EstimateFees("BTC/toPolygon", amountOn("1 BTC", multichain.Bitcoin), schedule)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(estimate.MintFee.IsZero()).To(BeTrue())
+			Expect(estimate.TotalFee).To(Equal(estimate.UnderlyingFee))
+		})
+	})
+
+	Context("when the fees cannot be estimated", func() {
+		It("should return an error", func() {
+			_, err := tx.EstimateFees("BTC/toEthereum", amountOn("0.00009 BTC", multichain.Bitcoin), schedule)
+			Expect(errors.Is(err, tx.ErrAmountUnderflow)).To(BeTrue())
+
+			_, err = tx.EstimateFees("BTC/toEthereum", amountOn("1 BTC", multichain.Ethereum), schedule)
+			Expect(err).To(HaveOccurred())
+			_, err = tx.EstimateFees("ZEC/toEthereum", amountOn("1 BTC", multichain.Bitcoin), schedule)
+			Expect(err).To(HaveOccurred())
+			_, err = tx.EstimateFees("BTC/claimFees", amountOn("1 BTC", multichain.Bitcoin), schedule)
+			Expect(err).To(HaveOccurred())
+
+			invalid := schedule
+			invalid.Chains = []tx.ChainFees{{Chain: multichain.Ethereum, MintFee: tx.MaxFeeBps + 1}}
+			Expect(invalid.Validate()).ToNot(Succeed())
+			_, err = tx.EstimateFees("BTC/toEthereum", amountOn("1 BTC", multichain.Bitcoin), invalid)
+			Expect(err).To(HaveOccurred())
+			invalid.Chains = []tx.ChainFees{{Chain: multichain.Ethereum}, {Chain: multichain.Ethereum}}
+			Expect(invalid.Validate()).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,332 @@
+//go:build go1.18
+// +build go1.18
+
+package tx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+)
+
+// The fuzz targets in this file are seeded with the corpora in
+// testdata/fuzz, and with transactions from txutil. They run as normal tests
+// with "go test", and can be fuzzed with "go test -fuzz FuzzTxSurge".
+
+// seedTxs returns deterministic transactions for seeding fuzz targets.
+func seedTxs() []tx.Tx {
+	r := rand.New(rand.NewSource(0))
+	txs := txutil.RandomGoodTxs(r, 8)
+	txs = append(txs, txutil.RandomBadTxs(r, 4)...)
+	unknownVersion := txutil.RandomGoodTx(r)
+	unknownVersion.Version = "2"
+	return append(txs, unknownVersion, tx.Tx{})
+}
+
+// withUnknownSurgeVersion returns surge data that is the same as the data of a
+// version 1 transaction, but with version "2". Surge marshals unknown versions
+// as the empty version, so this data cannot be produced by marshaling.
+func withUnknownSurgeVersion(f *testing.F, data []byte) []byte {
+	// The version follows the 32 byte hash, and is a 4 byte length and then
+	// the string.
+	if !bytes.Equal(data[32:37], []byte{0, 0, 0, 1, '1'}) {
+		f.Fatal("expected version 1")
+	}
+	data = append([]byte{}, data...)
+	data[36] = '2'
+	return data
+}
+
+func FuzzTxSurge(f *testing.F) {
+	for _, transaction := range seedTxs() {
+		data, err := surge.ToBinary(transaction)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		if transaction.Version == tx.Version1 {
+			f.Add(withUnknownSurgeVersion(f, data))
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		transaction := tx.Tx{}
+		if err := surge.FromBinary(&transaction, data); err != nil {
+			return
+		}
+		checkSurgeRoundTrip(t, &transaction, &tx.Tx{})
+		checkTxHash(t, transaction, reencodeSurge, true)
+	})
+}
+
+func FuzzTxJSON(f *testing.F) {
+	for _, transaction := range seedTxs() {
+		data, err := json.Marshal(transaction)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		transaction := tx.Tx{}
+		if err := json.Unmarshal(data, &transaction); err != nil {
+			return
+		}
+		checkJSONRoundTrip(t, &transaction, &tx.Tx{})
+		checkTxHash(t, transaction, reencodeSurge, true)
+		checkTxHash(t, transaction, reencodeJSON, false)
+	})
+}
+
+func FuzzWithStatusSurge(f *testing.F) {
+	for i, transaction := range seedTxs() {
+		data, err := surge.ToBinary(tx.WithStatus{Tx: transaction, Status: tx.Status(i % 6)})
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		if transaction.Version == tx.Version1 {
+			f.Add(withUnknownSurgeVersion(f, data))
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		transaction := tx.WithStatus{}
+		if err := surge.FromBinary(&transaction, data); err != nil {
+			return
+		}
+		checkSurgeRoundTrip(t, &transaction, &tx.WithStatus{})
+		checkTxHash(t, transaction.Tx, reencodeSurge, true)
+	})
+}
+
+func FuzzWithStatusJSON(f *testing.F) {
+	for i, transaction := range seedTxs() {
+		data, err := json.Marshal(tx.WithStatus{Tx: transaction, Status: tx.Status(i % 5)})
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		transaction := tx.WithStatus{}
+		if err := json.Unmarshal(data, &transaction); err != nil {
+			return
+		}
+		checkJSONRoundTrip(t, &transaction, &tx.WithStatus{})
+		checkTxHash(t, transaction.Tx, reencodeSurge, true)
+		checkTxHash(t, transaction.Tx, reencodeJSON, false)
+	})
+}
+
+func FuzzVersion(f *testing.F) {
+	for _, version := range []tx.Version{"", tx.Version0, tx.Version1, "2"} {
+		data, err := surge.ToBinary(version)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		version := tx.Version("")
+		if err := surge.FromBinary(&version, data); err != nil {
+			return
+		}
+		// Unknown versions are marshaled as the empty version.
+		if version.String() != string(version) && version.String() != "" {
+			t.Fatalf("unexpected string %q for version %q", version.String(), string(version))
+		}
+		checkSurgeRoundTrip(t, &version, new(tx.Version))
+		checkJSONRoundTrip(t, &version, new(tx.Version))
+	})
+}
+
+func FuzzStatus(f *testing.F) {
+	for _, status := range []tx.Status{tx.StatusNil, tx.StatusConfirming, tx.StatusPending, tx.StatusExecuting, tx.StatusDone} {
+		data, err := json.Marshal(status)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		status := tx.Status(0)
+		if err := surge.FromBinary(&status, data); err == nil {
+			checkSurgeRoundTrip(t, &status, new(tx.Status))
+		}
+		status = tx.Status(0)
+		if err := json.Unmarshal(data, &status); err == nil {
+			if status > tx.StatusDone {
+				t.Fatalf("unexpected status %v", uint8(status))
+			}
+			checkJSONRoundTrip(t, &status, new(tx.Status))
+		}
+	})
+}
+
+func FuzzSelector(f *testing.F) {
+	for _, selector := range txutil.AllSelectors() {
+		f.Add(string(selector))
+	}
+	f.Add("BTC/toEthereumFromSolana")
+	f.Add("BTC/Ethereum_syncWithChain")
+	f.Add("/to/from/")
+	f.Fuzz(func(t *testing.T, str string) {
+		selector := tx.Selector(str)
+		// None of these should panic.
+		_ = selector.Contract()
+		_ = selector.Fn()
+		_ = selector.IsIntrinsic()
+		_ = selector.IsCrossChain()
+		_ = selector.IsClaimFees()
+		if selector.IsLock() && selector.IsBurn() {
+			t.Fatalf("selector %q is both a lock and a burn", str)
+		}
+		if selector.IsMint() && selector.IsRelease() && !selector.IsClaimFees() && !selector.IsClaimFeesFromEvent() {
+			t.Fatalf("selector %q is both a mint and a release", str)
+		}
+		checkSurgeRoundTrip(t, &selector, new(tx.Selector))
+		checkJSONRoundTrip(t, &selector, new(tx.Selector))
+	})
+}
+
+func FuzzSelectorChains(f *testing.F) {
+	f.Add("BTC", "Ethereum", "Solana")
+	f.Add("DAI", "Ethereum", "")
+	f.Add("", "", "")
+	f.Fuzz(func(t *testing.T, asset, destination, source string) {
+		if strings.Contains(asset, "/") || strings.Contains(destination, "/") || strings.Contains(source, "/") {
+			return
+		}
+		if strings.Contains(destination, "From") || strings.Contains(source, "From") {
+			return
+		}
+		// Chains are matched by regular expressions that do not match new
+		// lines, and chain names never contain them.
+		if strings.Contains(destination, "\n") || strings.Contains(source, "\n") {
+			return
+		}
+		origin := multichain.Asset(asset).OriginChain()
+
+		selector := tx.Selector(asset + "/to" + destination)
+		if selector.Destination() != multichain.Chain(destination) || selector.Source() != origin {
+			t.Fatalf("selector %q: got source %q and destination %q", selector, selector.Source(), selector.Destination())
+		}
+		selector = tx.Selector(asset + "/from" + source)
+		if selector.Source() != multichain.Chain(source) || selector.Destination() != origin {
+			t.Fatalf("selector %q: got source %q and destination %q", selector, selector.Source(), selector.Destination())
+		}
+		selector = tx.Selector(asset + "/to" + destination + "From" + source)
+		if selector.Destination() != multichain.Chain(destination) || selector.Source() != multichain.Chain(source) {
+			t.Fatalf("selector %q: got source %q and destination %q", selector, selector.Source(), selector.Destination())
+		}
+	})
+}
+
+// checkSurgeRoundTrip checks that a decoded value can be re-encoded, and that
+// decoding and re-encoding again produces the same bytes.
+func checkSurgeRoundTrip(t *testing.T, v, w interface{}) {
+	data, err := surge.ToBinary(v)
+	if err != nil {
+		t.Fatalf("cannot re-encode %T: %v", v, err)
+	}
+	if err := surge.FromBinary(w, data); err != nil {
+		t.Fatalf("cannot decode re-encoded %T: %v", v, err)
+	}
+	dataAgain, err := surge.ToBinary(w)
+	if err != nil {
+		t.Fatalf("cannot re-encode %T: %v", v, err)
+	}
+	if !bytes.Equal(data, dataAgain) {
+		t.Fatalf("unstable encoding of %T: %x != %x", v, data, dataAgain)
+	}
+}
+
+// checkJSONRoundTrip checks that a decoded value can be re-encoded, and that
+// decoding and re-encoding again produces the same bytes.
+func checkJSONRoundTrip(t *testing.T, v, w interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("cannot re-encode %T: %v", v, err)
+	}
+	if err := json.Unmarshal(data, w); err != nil {
+		t.Fatalf("cannot decode re-encoded %T: %v", v, err)
+	}
+	dataAgain, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("cannot re-encode %T: %v", v, err)
+	}
+	if !bytes.Equal(data, dataAgain) {
+		t.Fatalf("unstable encoding of %T: %s != %s", v, data, dataAgain)
+	}
+}
+
+// checkTxHash checks that the hash of a decoded transaction is the same after
+// the transaction has been re-encoded and decoded again. Transactions decoded
+// from surge are only re-encoded with surge, because JSON cannot represent
+// strings that are not valid UTF-8. Surge re-encodes unknown versions as the
+// empty version, so normalizesVersion must be true for surge.
+func checkTxHash(t *testing.T, transaction tx.Tx, reencode func(tx.Tx) (tx.Tx, error), normalizesVersion bool) {
+	other, err := reencode(transaction)
+	if err != nil {
+		t.Fatalf("cannot re-encode: %v", err)
+	}
+	hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+	if err != nil {
+		t.Fatalf("cannot hash: %v", err)
+	}
+	unknown := transaction.Version.String() != string(transaction.Version)
+	if normalizesVersion && unknown {
+		if other.Version != "" {
+			t.Fatalf("unknown version %q re-encoded as %q", transaction.Version, other.Version)
+		}
+		// The empty version changes the hash, because NewTxHash pads the
+		// surge binary to the size hint of the version as it is given.
+		emptyHash, err := tx.NewTxHash(other.Version, other.Selector, other.Input)
+		if err != nil {
+			t.Fatalf("cannot hash: %v", err)
+		}
+		if emptyHash == hash {
+			t.Fatalf("unknown version %q has the hash of the empty version", transaction.Version)
+		}
+	} else if other.Version != transaction.Version {
+		t.Fatalf("unstable version: %q != %q", transaction.Version, other.Version)
+	}
+	// The hash recomputed from the original version is stable.
+	otherHash, err := tx.NewTxHash(transaction.Version, other.Selector, other.Input)
+	if err != nil {
+		t.Fatalf("cannot hash: %v", err)
+	}
+	if otherHash != hash {
+		t.Fatalf("unstable hash: %v != %v", hash, otherHash)
+	}
+	// The stored hash is never changed, even when the version is.
+	if other.Hash != transaction.Hash {
+		t.Fatalf("unstable stored hash: %v != %v", transaction.Hash, other.Hash)
+	}
+}
+
+func reencodeSurge(transaction tx.Tx) (tx.Tx, error) {
+	data, err := surge.ToBinary(transaction)
+	if err != nil {
+		return tx.Tx{}, err
+	}
+	other := tx.Tx{}
+	err = surge.FromBinary(&other, data)
+	return other, err
+}
+
+func reencodeJSON(transaction tx.Tx) (tx.Tx, error) {
+	data, err := json.Marshal(transaction)
+	if err != nil {
+		return tx.Tx{}, err
+	}
+	other := tx.Tx{}
+	err = json.Unmarshal(data, &other)
+	return other, err
+}
@@ -3,12 +3,17 @@ module github.com/renproject/tx
 go 1.16
 
 require (
+	github.com/btcsuite/btcd v0.22.0-beta
+	github.com/btcsuite/btcutil v1.0.3-0.20201208143702-a53e38424cce
 	github.com/onsi/ginkgo v1.16.5
 	github.com/onsi/gomega v1.16.0
 	github.com/renproject/id v0.4.2
 	github.com/renproject/multichain v0.4.3
 	github.com/renproject/pack v0.2.12
 	github.com/renproject/surge v1.2.7
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	google.golang.org/protobuf v1.26.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 replace github.com/gogo/protobuf => github.com/regen-network/protobuf v1.3.3-alpha.regen.1
@@ -0,0 +1,242 @@
+package tx
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sync"
+
+	"github.com/renproject/id"
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// hasherBufferSize is the initial size of the buffers of a Hasher. It is large
+// enough for all fixed size values, so only lists need larger buffers.
+const hasherBufferSize = 256
+
+// hasherZeros are written to pad the hash to the size hint.
+var hasherZeros [hasherBufferSize]byte
+
+// A Hasher computes transaction hashes without allocating memory. Instead of
+// marshaling the transaction into a buffer and hashing the buffer (see
+// NewTxHash), it writes the version, selector, and input straight into the
+// state of a SHA256 hash, and re-uses the hash state and a small scratch
+// buffer between calls. The resulting hashes are identical to those returned
+// by NewTxHash, including the zero padding that NewTxHash hashes when the size
+// hint of the input is larger than its binary representation.
+//
+// Inputs that contain lists still allocate, because the types of lists cannot
+// be marshaled without allocating. A Hasher is safe for concurrent use. The
+// zero value is ready to use.
+type Hasher struct {
+	pool sync.Pool
+}
+
+// hasherState is the re-usable state of a Hasher.
+type hasherState struct {
+	h   hash.Hash
+	buf []byte
+
+	// n is the number of bytes written to h, and rem is the remaining memory
+	// quota (see surge.MaxBytes).
+	n   int
+	rem int
+}
+
+// Hash returns the hash of a transaction with the given version, selector, and
+// input. It is the same as the hash returned by NewTxHash.
+func (hasher *Hasher) Hash(version Version, selector Selector, input pack.Typed) (id.Hash, error) {
+	state, ok := hasher.pool.Get().(*hasherState)
+	if !ok {
+		state = &hasherState{h: sha256.New(), buf: make([]byte, hasherBufferSize)}
+	}
+	defer hasher.pool.Put(state)
+
+	state.h.Reset()
+	state.n = 0
+	state.rem = surge.MaxBytes
+	// The version is written in the same way as Version.Marshal, so unknown
+	// versions are written as empty strings.
+	if err := state.writeString(version.String()); err != nil {
+		return id.Hash{}, err
+	}
+	if err := state.writeString(string(selector)); err != nil {
+		return id.Hash{}, err
+	}
+	if err := state.writeStructType(pack.Struct(input)); err != nil {
+		return id.Hash{}, err
+	}
+	if err := state.writeStruct(pack.Struct(input)); err != nil {
+		return id.Hash{}, err
+	}
+
+	// NewTxHash hashes the whole buffer, which is allocated using the size
+	// hint (of the version as given, not as written), so any bytes that were
+	// not marshaled are hashed as zeros.
+	size := surge.SizeHintString(string(version)) + surge.SizeHintString(string(selector)) + structTypeSizeHint(pack.Struct(input)) + pack.Struct(input).SizeHint()
+	if state.n > size {
+		return id.Hash{}, surge.ErrUnexpectedEndOfBuffer
+	}
+	for pad := size - state.n; pad > 0; {
+		n := pad
+		if n > len(hasherZeros) {
+			n = len(hasherZeros)
+		}
+		state.h.Write(hasherZeros[:n])
+		pad -= n
+	}
+
+	hash := id.Hash{}
+	copy(hash[:], state.h.Sum(state.buf[:0]))
+	return hash, nil
+}
+
+// VerifyHash returns an error wrapping ErrInvalidHash if the hash of the
+// transaction is not the hash of its version, selector, and input. It is the
+// same as Tx.VerifyHash, but does not allocate memory.
+func (hasher *Hasher) VerifyHash(tx Tx) error {
+	hash, err := hasher.Hash(tx.Version, tx.Selector, tx.Input)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if hash != tx.Hash {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidHash, hash, tx.Hash)
+	}
+	return nil
+}
+
+// write bytes to the hash, consuming the memory quota.
+func (state *hasherState) write(data []byte) error {
+	if state.rem < len(data) {
+		return surge.ErrUnexpectedEndOfBuffer
+	}
+	state.h.Write(data)
+	state.n += len(data)
+	state.rem -= len(data)
+	return nil
+}
+
+// writeLen writes a length prefix, in the same way as surge.MarshalLen.
+func (state *hasherState) writeLen(l int) error {
+	if _, _, err := surge.MarshalLen(uint32(l), state.buf, state.rem); err != nil {
+		return err
+	}
+	return state.write(state.buf[:surge.SizeHintU32])
+}
+
+// writeString writes a string in the same way as surge.MarshalString. The
+// string is copied to the hash through the scratch buffer, because converting
+// it to bytes would allocate.
+func (state *hasherState) writeString(str string) error {
+	if err := state.writeLen(len(str)); err != nil {
+		return err
+	}
+	if state.rem < len(str) {
+		return surge.ErrUnexpectedEndOfBuffer
+	}
+	for len(str) > 0 {
+		n := copy(state.buf, str)
+		if err := state.write(state.buf[:n]); err != nil {
+			return err
+		}
+		str = str[n:]
+	}
+	return nil
+}
+
+// grow the scratch buffer to at least the given size.
+func (state *hasherState) grow(size int) {
+	if size > len(state.buf) {
+		state.buf = make([]byte, size)
+	}
+}
+
+// writeType writes the type of a value, in the same way as pack.MarshalType.
+// Struct types are written by walking the fields of the value, because
+// pack.Struct.Type allocates.
+func (state *hasherState) writeType(value pack.Value) error {
+	if v, ok := value.(pack.Struct); ok {
+		return state.writeStructType(v)
+	}
+	t := value.Type()
+	state.grow(pack.SizeHintType(t))
+	tail, _, err := pack.MarshalType(t, state.buf, state.rem)
+	if err != nil {
+		return err
+	}
+	return state.write(state.buf[:len(state.buf)-len(tail)])
+}
+
+// writeStructType writes the type of a struct. It takes a struct, instead of a
+// value, so that the struct does not escape to the heap.
+func (state *hasherState) writeStructType(v pack.Struct) error {
+	state.buf[0] = byte(pack.KindStruct)
+	if err := state.write(state.buf[:1]); err != nil {
+		return err
+	}
+	if err := state.writeLen(len(v)); err != nil {
+		return err
+	}
+	for _, field := range v {
+		if err := state.writeString(field.Name); err != nil {
+			return err
+		}
+		if err := state.writeType(field.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeValue writes a value, in the same way as its Marshal method. Bytes are
+// written directly to the hash, without being copied.
+func (state *hasherState) writeValue(value pack.Value) error {
+	switch v := value.(type) {
+	case pack.Bytes:
+		if err := state.writeLen(len(v)); err != nil {
+			return err
+		}
+		return state.write(v)
+	case pack.String:
+		return state.writeString(string(v))
+	case pack.Struct:
+		return state.writeStruct(v)
+	default:
+		state.grow(value.SizeHint())
+		tail, _, err := value.Marshal(state.buf, state.rem)
+		if err != nil {
+			return err
+		}
+		return state.write(state.buf[:len(state.buf)-len(tail)])
+	}
+}
+
+// writeStruct writes the fields of a struct, in the same way as
+// pack.Struct.Marshal.
+func (state *hasherState) writeStruct(v pack.Struct) error {
+	for _, field := range v {
+		if err := state.writeValue(field.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeSizeHint returns the size hint of the type of a value, in the same way
+// as pack.SizeHintType, without allocating for struct types.
+func typeSizeHint(value pack.Value) int {
+	if v, ok := value.(pack.Struct); ok {
+		return structTypeSizeHint(v)
+	}
+	return pack.SizeHintType(value.Type())
+}
+
+// structTypeSizeHint returns the size hint of the type of a struct.
+func structTypeSizeHint(v pack.Struct) int {
+	size := pack.KindStruct.SizeHint() + surge.SizeHintU32
+	for _, field := range v {
+		size += surge.SizeHintString(field.Name) + typeSizeHint(field.Value)
+	}
+	return size
+}
@@ -0,0 +1,146 @@
+package tx_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hashers", func() {
+
+	Context("when hashing transactions", func() {
+		It("should return the same hash as NewTxHash", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			hasher := tx.Hasher{}
+			for i := 0; i < 100; i++ {
+				transaction := txutil.RandomTx(r)
+				expected, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+				Expect(err).ToNot(HaveOccurred())
+				hash, err := hasher.Hash(transaction.Version, transaction.Selector, transaction.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(expected))
+			}
+		})
+
+		It("should match the golden vectors", func() {
+			vectors := readGoldenVectors()
+			hasher := tx.Hasher{}
+			for _, vector := range vectors {
+				input := pack.Typed{}
+				Expect(input.UnmarshalJSON(vector.Input)).To(Succeed())
+				expected, err := tx.NewTxHash(vector.Version, vector.Selector, input)
+				Expect(err).ToNot(HaveOccurred())
+				hash, err := hasher.Hash(vector.Version, vector.Selector, input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(expected), vector.Name)
+			}
+		})
+
+		It("should hash nested structs and lists, including their padding", func() {
+			hasher := tx.Hasher{}
+			input := pack.NewTyped(
+				"nested", pack.NewStruct(
+					"name", pack.String("nested"),
+					"amount", pack.U256{},
+					"inner", pack.NewStruct("flag", pack.NewBool(true)),
+				),
+				"list", pack.List{T: pack.U64(0).Type(), Elems: []pack.Value{pack.NewU64(1), pack.NewU64(2)}},
+				"empty", pack.List{T: pack.Bytes{}.Type()},
+				"payload", pack.NewBytes(make([]byte, 1000)),
+			)
+			expected, err := tx.NewTxHash(tx.Version1, "BTC/toEthereum", input)
+			Expect(err).ToNot(HaveOccurred())
+			hash, err := hasher.Hash(tx.Version1, "BTC/toEthereum", input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hash).To(Equal(expected))
+		})
+
+		It("should return the same hash as NewTxHash for any version", func() {
+			hasher := tx.Hasher{}
+			input := pack.NewTyped("amount", pack.NewU256FromU64(1), "payload", pack.NewBytes([]byte{1, 2, 3}))
+			for _, version := range []tx.Version{"", "0", "1", "2", "abc", tx.Version(strings.Repeat("x", 1000))} {
+				expected, err := tx.NewTxHash(version, "BTC/toEthereum", input)
+				Expect(err).ToNot(HaveOccurred())
+				hash, err := hasher.Hash(version, "BTC/toEthereum", input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(expected), "version %q", version)
+			}
+		})
+
+		It("should return an error when the hash is wrong", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			hasher := tx.Hasher{}
+			transaction := txutil.RandomValidTx(r)
+			Expect(hasher.VerifyHash(transaction)).To(Succeed())
+			transaction.Hash[0] ^= 1
+			Expect(errors.Is(hasher.VerifyHash(transaction), tx.ErrInvalidHash)).To(BeTrue())
+		})
+
+		It("should not allocate memory", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			hasher := tx.Hasher{}
+			for _, transaction := range []tx.Tx{txutil.RandomValidTx(r), benchmarkTxs()["large"]} {
+				allocs := testing.AllocsPerRun(100, func() {
+					if _, err := hasher.Hash(transaction.Version, transaction.Selector, transaction.Input); err != nil {
+						panic(err)
+					}
+				})
+				Expect(allocs).To(BeZero())
+			}
+		})
+
+		It("should be safe for concurrent use", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxs(r, 8)
+			hasher := tx.Hasher{}
+			wg := sync.WaitGroup{}
+			for i := range txs {
+				wg.Add(1)
+				go func(transaction tx.Tx) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						Expect(hasher.VerifyHash(transaction)).To(Succeed())
+					}
+				}(txs[i])
+			}
+			wg.Wait()
+		})
+	})
+})
+
+func BenchmarkNewTxHash(b *testing.B) {
+	for name, transaction := range benchmarkTxs() {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHasher(b *testing.B) {
+	for name, transaction := range benchmarkTxs() {
+		b.Run(name, func(b *testing.B) {
+			hasher := tx.Hasher{}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := hasher.Hash(transaction.Version, transaction.Selector, transaction.Input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package tx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"sync"
+
+	"github.com/renproject/id"
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// hashMemoSeed seeds the fingerprints of all memos. It is random for every
+// process, so fingerprint collisions cannot be chosen in advance.
+var hashMemoSeed = maphash.MakeSeed()
+
+// A HashMemo memoises the hash of a transaction. It keeps the hash together
+// with a fingerprint of the hashed content (the version, selector, and input),
+// and only hashes again when the fingerprint changes. This makes repeated
+// hashing of the same transaction cheap (for example, verification followed
+// by indexing), while remaining correct when a different or modified
+// transaction is given.
+//
+// The fingerprint is a 64-bit non-cryptographic hash that is computed directly
+// from the values of the input, without marshaling them. A HashMemo reuses its
+// buffer between calls, and is safe for concurrent use. The zero value is ready
+// to use.
+type HashMemo struct {
+	mu          sync.Mutex
+	h           maphash.Hash
+	buf         []byte
+	fingerprint uint64
+	hash        id.Hash
+	ok          bool
+}
+
+// Hash returns the hash of the version, selector, and input of the
+// transaction. It is the same as the hash returned by NewTxHash.
+func (memo *HashMemo) Hash(tx Tx) (id.Hash, error) {
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+
+	if !memo.ok {
+		memo.h.SetSeed(hashMemoSeed)
+	}
+	memo.h.Reset()
+	writeFingerprintString(&memo.h, string(tx.Version))
+	writeFingerprintString(&memo.h, string(tx.Selector))
+	if err := writeFingerprint(&memo.h, pack.Struct(tx.Input)); err != nil {
+		memo.ok = false
+		return id.Hash{}, err
+	}
+	fingerprint := memo.h.Sum64()
+	if memo.ok && memo.fingerprint == fingerprint {
+		return memo.hash, nil
+	}
+
+	// The buffer must have exactly the size of the buffer that is used by
+	// NewTxHash, and must be zeroed, because the whole buffer is hashed.
+	n := surge.SizeHintString(string(tx.Version)) + surge.SizeHintString(string(tx.Selector)) + tx.Input.SizeHint()
+	if cap(memo.buf) < n {
+		memo.buf = make([]byte, n)
+	} else {
+		memo.buf = memo.buf[:n]
+		for i := range memo.buf {
+			memo.buf[i] = 0
+		}
+	}
+	hash, err := NewTxHashIntoBuffer(tx.Version, tx.Selector, tx.Input, memo.buf)
+	if err != nil {
+		memo.ok = false
+		return id.Hash{}, err
+	}
+	memo.hash, memo.fingerprint, memo.ok = hash, fingerprint, true
+	return hash, nil
+}
+
+// VerifyHash returns an error wrapping ErrInvalidHash if the hash of the
+// transaction is not the hash of its version, selector, and input. It is the
+// same as Tx.VerifyHash, but uses the memoised hash when possible.
+func (memo *HashMemo) VerifyHash(tx Tx) error {
+	hash, err := memo.Hash(tx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if hash != tx.Hash {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidHash, hash, tx.Hash)
+	}
+	return nil
+}
+
+// writeFingerprint writes an unambiguous representation of a value to a hash.
+// Every value is prefixed with its kind, and variable length values are
+// prefixed with their length, so different values never have the same
+// representation.
+func writeFingerprint(h *maphash.Hash, value pack.Value) error {
+	var scratch [9]byte
+	writeUint := func(kind pack.Kind, x uint64) {
+		scratch[0] = byte(kind)
+		binary.BigEndian.PutUint64(scratch[1:], x)
+		h.Write(scratch[:])
+	}
+	switch v := value.(type) {
+	case pack.Bool:
+		x := uint64(0)
+		if v {
+			x = 1
+		}
+		writeUint(pack.KindBool, x)
+	case pack.U8:
+		writeUint(pack.KindU8, uint64(v))
+	case pack.U16:
+		writeUint(pack.KindU16, uint64(v))
+	case pack.U32:
+		writeUint(pack.KindU32, uint64(v))
+	case pack.U64:
+		writeUint(pack.KindU64, uint64(v))
+	case pack.U128:
+		// The zero value has no inner integer, and is marshaled as zero.
+		var x [16]byte
+		if v != (pack.U128{}) {
+			x = v.Bytes16()
+		}
+		h.WriteByte(byte(pack.KindU128))
+		h.Write(x[:])
+	case pack.U256:
+		var x [32]byte
+		if v != (pack.U256{}) {
+			x = v.Bytes32()
+		}
+		h.WriteByte(byte(pack.KindU256))
+		h.Write(x[:])
+	case pack.String:
+		writeFingerprintString(h, string(v))
+	case pack.Bytes:
+		writeUint(pack.KindBytes, uint64(len(v)))
+		h.Write(v)
+	case pack.Bytes32:
+		h.WriteByte(byte(pack.KindBytes32))
+		h.Write(v[:])
+	case pack.Bytes65:
+		h.WriteByte(byte(pack.KindBytes65))
+		h.Write(v[:])
+	case pack.Struct:
+		writeUint(pack.KindStruct, uint64(len(v)))
+		for _, field := range v {
+			writeFingerprintString(h, field.Name)
+			if err := writeFingerprint(h, field.Value); err != nil {
+				return err
+			}
+		}
+	case pack.Typed:
+		return writeFingerprint(h, pack.Struct(v))
+	default:
+		// Lists are rare in inputs, and the type of their elements is not
+		// exposed, so they are marshaled.
+		data, err := surge.ToBinary(value)
+		if err != nil {
+			return err
+		}
+		writeUint(pack.KindNil, uint64(len(data)))
+		h.Write(data)
+	}
+	return nil
+}
+
+// writeFingerprintString writes a string to a hash, prefixed with its kind and
+// length, so that adjacent strings cannot be confused with each other.
+func writeFingerprintString(h *maphash.Hash, str string) {
+	var scratch [9]byte
+	scratch[0] = byte(pack.KindString)
+	binary.BigEndian.PutUint64(scratch[1:], uint64(len(str)))
+	h.Write(scratch[:])
+	h.WriteString(str)
+}
@@ -0,0 +1,168 @@
+package tx_test
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hash memos", func() {
+
+	Context("when hashing transactions", func() {
+		It("should return the same hash as NewTxHash", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			memo := tx.HashMemo{}
+			for i := 0; i < 100; i++ {
+				transaction := txutil.RandomTx(r)
+				expected, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+				Expect(err).ToNot(HaveOccurred())
+				for j := 0; j < 2; j++ {
+					hash, err := memo.Hash(transaction)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(hash).To(Equal(expected))
+				}
+			}
+		})
+
+		It("should match the golden vectors", func() {
+			vectors := readGoldenVectors()
+			memo := tx.HashMemo{}
+			for _, vector := range vectors {
+				input := pack.Typed{}
+				Expect(input.UnmarshalJSON(vector.Input)).To(Succeed())
+				hash, err := memo.Hash(tx.Tx{Version: vector.Version, Selector: vector.Selector, Input: input})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash.String()).ToNot(BeEmpty())
+				expected, err := tx.NewTxHash(vector.Version, vector.Selector, input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(expected), vector.Name)
+			}
+		})
+
+		It("should hash again when the transaction is modified", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			memo := tx.HashMemo{}
+			transaction := txutil.RandomValidTx(r)
+			Expect(memo.VerifyHash(transaction)).To(Succeed())
+
+			// Modify the input in place, without changing its size.
+			modified := make([]byte, len(transaction.Input.Get("txid").(pack.Bytes)))
+			copy(modified, transaction.Input.Get("txid").(pack.Bytes))
+			modified[0] ^= 1
+			for i := range transaction.Input {
+				if transaction.Input[i].Name == "txid" {
+					transaction.Input[i] = pack.NewStructField("txid", pack.NewBytes(modified))
+				}
+			}
+			err := memo.VerifyHash(transaction)
+			Expect(errors.Is(err, tx.ErrInvalidHash)).To(BeTrue())
+			Expect(transaction.VerifyHash()).To(MatchError(tx.ErrInvalidHash))
+		})
+
+		It("should not hash stale bytes from a larger transaction", func() {
+			memo := tx.HashMemo{}
+			large := txutil.NewGoodTx("BTC/toEthereum", pack.NewTyped("payload", pack.NewBytes(make([]byte, 1024))))
+			small := txutil.NewGoodTx("BTC/toEthereum", pack.NewTyped("payload", pack.NewBytes([]byte{1})))
+			Expect(memo.VerifyHash(large)).To(Succeed())
+			Expect(memo.VerifyHash(small)).To(Succeed())
+			Expect(memo.VerifyHash(large)).To(Succeed())
+		})
+
+		It("should not confuse the boundary between the version and selector", func() {
+			memo := tx.HashMemo{}
+			input := pack.NewTyped("amount", pack.NewU256FromU64(1))
+			first := tx.Tx{Version: "1", Selector: "X\x00Y", Input: input}
+			first.Hash, _ = tx.NewTxHash(first.Version, first.Selector, first.Input)
+			second := tx.Tx{Version: "1\x00X", Selector: "Y", Input: input, Hash: first.Hash}
+			Expect(memo.VerifyHash(first)).To(Succeed())
+			Expect(second.VerifyHash()).To(MatchError(tx.ErrInvalidHash))
+			Expect(memo.VerifyHash(second)).To(MatchError(tx.ErrInvalidHash))
+		})
+
+		It("should hash zero-value integers as zero", func() {
+			memo := tx.HashMemo{}
+			zero := txutil.NewGoodTx("BTC/toEthereum", pack.NewTyped("amount", pack.U256{}, "fee", pack.U128{}))
+			Expect(memo.VerifyHash(zero)).To(Succeed())
+			nonZero := txutil.NewGoodTx("BTC/toEthereum", pack.NewTyped("amount", pack.NewU256FromU64(1), "fee", pack.U128{}))
+			Expect(memo.VerifyHash(nonZero)).To(Succeed())
+			Expect(memo.VerifyHash(zero)).To(Succeed())
+		})
+
+		It("should be safe for concurrent use", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxs(r, 8)
+			memo := tx.HashMemo{}
+			wg := sync.WaitGroup{}
+			for i := range txs {
+				wg.Add(1)
+				go func(transaction tx.Tx) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						Expect(memo.VerifyHash(transaction)).To(Succeed())
+					}
+				}(txs[i])
+			}
+			wg.Wait()
+		})
+	})
+})
+
+// benchmarkTxs returns a transaction with a small input, and a transaction
+// with a payload of the maximum size.
+func benchmarkTxs() map[string]tx.Tx {
+	r := rand.New(rand.NewSource(0))
+	small := txutil.RandomValidTx(r)
+	large := small
+	large.Input = make(pack.Typed, len(small.Input))
+	copy(large.Input, small.Input)
+	for i := range large.Input {
+		if large.Input[i].Name == "payload" {
+			large.Input[i] = pack.NewStructField("payload", pack.NewBytes(make([]byte, tx.MaxPayloadBytes)))
+		}
+	}
+	large.Hash, _ = tx.NewTxHash(large.Version, large.Selector, large.Input)
+	return map[string]tx.Tx{"small": small, "large": large}
+}
+
+func BenchmarkVerifyHashIntoBuffer(b *testing.B) {
+	for name, transaction := range benchmarkTxs() {
+		b.Run(name, func(b *testing.B) {
+			buf := make([]byte, surge.SizeHintString(string(transaction.Version))+surge.SizeHintString(string(transaction.Selector))+surge.SizeHint(transaction.Input))
+			b.SetBytes(int64(len(buf)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				hash, err := tx.NewTxHashIntoBuffer(transaction.Version, transaction.Selector, transaction.Input, buf)
+				if err != nil || hash != transaction.Hash {
+					b.Fatal("bad hash")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyHashMemo(b *testing.B) {
+	for name, transaction := range benchmarkTxs() {
+		b.Run(name, func(b *testing.B) {
+			memo := tx.HashMemo{}
+			b.SetBytes(int64(surge.SizeHint(transaction.Input)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := memo.VerifyHash(transaction); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
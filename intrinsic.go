@@ -0,0 +1,202 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+)
+
+// SyncWithChainInput is the input of a syncWithChain intrinsic transaction. It
+// updates the state of a contract with the latest state of its chain.
+type SyncWithChainInput struct {
+	// LatestHeight of the chain that has been observed.
+	LatestHeight pack.U64 `json:"latestHeight"`
+	// GasLimit is the maximum amount of gas used by transactions on the
+	// chain.
+	GasLimit pack.U256 `json:"gasLimit"`
+	// GasCap is the maximum price that will be paid per unit of gas.
+	GasCap pack.U256 `json:"gasCap"`
+	// GasPrice is the current price per unit of gas.
+	GasPrice pack.U256 `json:"gasPrice"`
+}
+
+// Validate returns an error if the latest height is zero.
+func (input SyncWithChainInput) Validate() error {
+	if input.LatestHeight == 0 {
+		return fmt.Errorf("latest height is zero")
+	}
+	return nil
+}
+
+// ChangeFeesInput is the input of a changeFees intrinsic transaction. It
+// replaces the fee schedule of the asset of the contract.
+type ChangeFeesInput struct {
+	Fees []ChainFees `json:"fees"`
+}
+
+// NewChangeFeesInput returns the input that changes the fees of an asset to the
+// given fee schedule.
+func NewChangeFeesInput(schedule FeeSchedule) ChangeFeesInput {
+	fees := make([]ChainFees, len(schedule.Chains))
+	copy(fees, schedule.Chains)
+	return ChangeFeesInput{Fees: fees}
+}
+
+// Schedule returns the fee schedule for an asset that is defined by the input.
+func (input ChangeFeesInput) Schedule(asset multichain.Asset) FeeSchedule {
+	return FeeSchedule{Asset: asset, Chains: input.Fees}
+}
+
+// Validate returns an error if the fee schedule that is defined by the input is
+// not valid.
+func (input ChangeFeesInput) Validate() error {
+	return input.Schedule("").Validate()
+}
+
+// EpochInput is the input of an epoch intrinsic transaction. It begins a new
+// epoch.
+type EpochInput struct {
+	// Number of the epoch. It increases by one with every epoch.
+	Number pack.U64 `json:"number"`
+	// Hash of the epoch.
+	Hash pack.Bytes32 `json:"hash"`
+}
+
+// Validate returns an error if the hash of the epoch is empty.
+func (input EpochInput) Validate() error {
+	if input.Hash == (pack.Bytes32{}) {
+		return fmt.Errorf("epoch hash is empty")
+	}
+	return nil
+}
+
+// ChangeSignatoriesInput is the input of a changeSignatories intrinsic
+// transaction. It changes the public key that signs mints.
+type ChangeSignatoriesInput struct {
+	// Epoch in which the signatories change.
+	Epoch pack.U64 `json:"epoch"`
+	// PubKey is the compressed secp256k1 public key of the new signatories.
+	PubKey pack.Bytes `json:"pubKey"`
+}
+
+// Validate returns an error if the public key is not a compressed secp256k1
+// public key.
+func (input ChangeSignatoriesInput) Validate() error {
+	if len(input.PubKey) != 33 || (input.PubKey[0] != 0x02 && input.PubKey[0] != 0x03) {
+		return fmt.Errorf("expected compressed secp256k1 public key, got %v bytes", len(input.PubKey))
+	}
+	return nil
+}
+
+// An intrinsicInput is one of the typed inputs of an intrinsic transaction.
+type intrinsicInput interface {
+	Validate() error
+}
+
+// EncodeIntrinsicInput validates a typed intrinsic input, and encodes it into
+// the untyped input of a transaction.
+func EncodeIntrinsicInput(input interface{}) (pack.Typed, error) {
+	switch input.(type) {
+	case SyncWithChainInput, ChangeFeesInput, EpochInput, ChangeSignatoriesInput:
+	default:
+		return nil, fmt.Errorf("unexpected intrinsic input of type %T", input)
+	}
+	if err := input.(intrinsicInput).Validate(); err != nil {
+		return nil, fmt.Errorf("invalid input: %v", err)
+	}
+	value, err := pack.Encode(input)
+	if err != nil {
+		return nil, fmt.Errorf("encoding input: %v", err)
+	}
+	return pack.Typed(value.(pack.Struct)), nil
+}
+
+// DecodeIntrinsicInput decodes the untyped input of a transaction into a typed
+// intrinsic input, and validates it. The input must be a pointer to one of the
+// intrinsic input types, and the untyped input must have exactly the same type.
+func DecodeIntrinsicInput(typed pack.Typed, input interface{}) error {
+	var expected interface{}
+	switch input.(type) {
+	case *SyncWithChainInput:
+		expected = SyncWithChainInput{}
+	case *ChangeFeesInput:
+		expected = ChangeFeesInput{}
+	case *EpochInput:
+		expected = EpochInput{}
+	case *ChangeSignatoriesInput:
+		expected = ChangeSignatoriesInput{}
+	default:
+		return fmt.Errorf("unexpected intrinsic input of type %T", input)
+	}
+	zero, err := pack.Encode(expected)
+	if err != nil {
+		return fmt.Errorf("encoding input: %v", err)
+	}
+	if !equalTypes(pack.Struct(typed).Type(), zero.Type()) {
+		return fmt.Errorf("expected input of type %v, got input of type %v", zero.Type(), pack.Struct(typed).Type())
+	}
+	if err := pack.Decode(input, typed); err != nil {
+		return fmt.Errorf("decoding input: %v", err)
+	}
+	if err := input.(intrinsicInput).Validate(); err != nil {
+		return fmt.Errorf("invalid input: %v", err)
+	}
+	return nil
+}
+
+// IntrinsicInput returns the typed input of an intrinsic transaction. It is a
+// SyncWithChainInput, ChangeFeesInput, EpochInput, or ChangeSignatoriesInput,
+// depending on the function of the selector.
+func (tx Tx) IntrinsicInput() (interface{}, error) {
+	switch tx.Selector.Fn() {
+	case SyncWithChainFn:
+		input := SyncWithChainInput{}
+		err := DecodeIntrinsicInput(tx.Input, &input)
+		return input, err
+	case ChangeFeesFn:
+		input := ChangeFeesInput{}
+		err := DecodeIntrinsicInput(tx.Input, &input)
+		return input, err
+	case EpochFn:
+		input := EpochInput{}
+		err := DecodeIntrinsicInput(tx.Input, &input)
+		return input, err
+	case ChangeSignatoriesFn:
+		input := ChangeSignatoriesInput{}
+		err := DecodeIntrinsicInput(tx.Input, &input)
+		return input, err
+	default:
+		return nil, fmt.Errorf("selector %v is not intrinsic", tx.Selector)
+	}
+}
+
+// NewSyncWithChainTx returns a syncWithChain intrinsic transaction for a
+// contract.
+func NewSyncWithChainTx(contract string, input SyncWithChainInput) (Tx, error) {
+	return newIntrinsicTx(contract, SyncWithChainFn, input)
+}
+
+// NewChangeFeesTx returns a changeFees intrinsic transaction for a contract.
+func NewChangeFeesTx(contract string, input ChangeFeesInput) (Tx, error) {
+	return newIntrinsicTx(contract, ChangeFeesFn, input)
+}
+
+// NewEpochTx returns an epoch intrinsic transaction for a contract.
+func NewEpochTx(contract string, input EpochInput) (Tx, error) {
+	return newIntrinsicTx(contract, EpochFn, input)
+}
+
+// NewChangeSignatoriesTx returns a changeSignatories intrinsic transaction for
+// a contract.
+func NewChangeSignatoriesTx(contract string, input ChangeSignatoriesInput) (Tx, error) {
+	return newIntrinsicTx(contract, ChangeSignatoriesFn, input)
+}
+
+func newIntrinsicTx(contract, fn string, input interface{}) (Tx, error) {
+	typed, err := EncodeIntrinsicInput(input)
+	if err != nil {
+		return Tx{}, err
+	}
+	return NewTx(Selector(fmt.Sprintf("%v/%v", contract, fn)), typed)
+}
@@ -0,0 +1,127 @@
+package tx_test
+
+import (
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Intrinsic transactions", func() {
+
+	syncWithChain := tx.SyncWithChainInput{
+		LatestHeight: 100,
+		GasLimit:     pack.NewU256FromUint64(21000),
+		GasCap:       pack.NewU256FromUint64(100),
+		GasPrice:     pack.NewU256FromUint64(50),
+	}
+	changeFees := tx.NewChangeFeesInput(tx.FeeSchedule{
+		Asset: multichain.BTC,
+		Chains: []tx.ChainFees{
+			{Chain: multichain.Ethereum, UnderlyingFee: pack.NewU256FromUint64(10000), MintFee: 15, BurnFee: 10},
+			{Chain: multichain.Solana, UnderlyingFee: pack.NewU256FromUint64(20000), MintFee: 20, BurnFee: 5},
+		},
+	})
+	epoch := tx.EpochInput{Number: 42, Hash: pack.Bytes32{1, 2, 3}}
+	changeSignatories := tx.ChangeSignatoriesInput{Epoch: 42, PubKey: append(pack.Bytes{0x02}, make([]byte, 32)...)}
+
+	Context("when constructing intrinsic transactions", func() {
+		It("should set the selector and decode the typed input", func() {
+			transaction, err := tx.NewSyncWithChainTx("BTC", syncWithChain)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Selector).To(Equal(tx.Selector("BTC/syncWithChain")))
+			Expect(transaction.Selector.IsIntrinsic()).To(BeTrue())
+			input, err := transaction.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(Equal(syncWithChain))
+
+			transaction, err = tx.NewChangeFeesTx("BTC", changeFees)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Selector).To(Equal(tx.Selector("BTC/changeFees")))
+			input, err = transaction.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(Equal(changeFees))
+			Expect(input.(tx.ChangeFeesInput).Schedule(multichain.BTC).ChainFees(multichain.Solana).MintFee).To(Equal(pack.U16(20)))
+			Expect(input.(tx.ChangeFeesInput).Schedule(multichain.BTC).ChainFees(multichain.Solana).UnderlyingFee).To(Equal(pack.NewU256FromUint64(20000)))
+
+			transaction, err = tx.NewEpochTx("System", epoch)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Selector).To(Equal(tx.Selector("System/epoch")))
+			input, err = transaction.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(Equal(epoch))
+
+			transaction, err = tx.NewChangeSignatoriesTx("System", changeSignatories)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Selector).To(Equal(tx.Selector("System/changeSignatories")))
+			input, err = transaction.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(Equal(changeSignatories))
+		})
+
+		It("should compute the transaction hash", func() {
+			transaction, err := tx.NewEpochTx("System", epoch)
+			Expect(err).ToNot(HaveOccurred())
+			hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Hash).To(Equal(hash))
+		})
+
+		It("should encode empty fee lists", func() {
+			input := tx.ChangeFeesInput{Fees: []tx.ChainFees{}}
+			transaction, err := tx.NewChangeFeesTx("BTC", input)
+			Expect(err).ToNot(HaveOccurred())
+			decoded, err := transaction.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.(tx.ChangeFeesInput).Fees).To(BeEmpty())
+		})
+	})
+
+	Context("when the input is invalid", func() {
+		It("should return an error", func() {
+			_, err := tx.NewSyncWithChainTx("BTC", tx.SyncWithChainInput{})
+			Expect(err).To(HaveOccurred())
+			_, err = tx.NewEpochTx("System", tx.EpochInput{Number: 1})
+			Expect(err).To(HaveOccurred())
+			_, err = tx.NewChangeSignatoriesTx("System", tx.ChangeSignatoriesInput{PubKey: pack.Bytes{0x04}})
+			Expect(err).To(HaveOccurred())
+			_, err = tx.NewChangeFeesTx("BTC", tx.ChangeFeesInput{
+				Fees: []tx.ChainFees{{Chain: multichain.Ethereum, UnderlyingFee: pack.NewU256FromUint64(1), BurnFee: tx.MaxFeeBps + 1}},
+			})
+			Expect(err).To(HaveOccurred())
+			_, err = tx.EncodeIntrinsicInput(struct{}{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when decoding an input of the wrong type", func() {
+		It("should return an error", func() {
+			input := tx.EpochInput{}
+			Expect(tx.DecodeIntrinsicInput(pack.NewTyped("number", pack.U64(1)), &input)).ToNot(Succeed())
+			Expect(tx.DecodeIntrinsicInput(pack.NewTyped(
+				"number", pack.U32(1),
+				"hash", pack.Bytes32{1},
+			), &input)).ToNot(Succeed())
+			Expect(tx.DecodeIntrinsicInput(pack.NewTyped(
+				"number", pack.U64(1),
+				"hash", pack.Bytes32{1},
+				"extra", pack.Bool(true),
+			), &input)).ToNot(Succeed())
+			Expect(tx.DecodeIntrinsicInput(pack.NewTyped(
+				"number", pack.U64(1),
+				"hash", pack.Bytes32{1},
+			), &input)).To(Succeed())
+			Expect(tx.DecodeIntrinsicInput(pack.Typed{}, new(int))).ToNot(Succeed())
+
+			transaction, err := tx.NewTx("BTC/toEthereum", pack.NewTyped("number", pack.U64(1)))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = transaction.IntrinsicInput()
+			Expect(err).To(HaveOccurred())
+			transaction.Selector = "System/epoch"
+			_, err = transaction.IntrinsicInput()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,185 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidMintSignature is returned when the signature of a mint was not
+// produced by the expected public key, or was not produced over the expected
+// sighash.
+var ErrInvalidMintSignature = errors.New("invalid mint signature")
+
+// NewMintSelectorHash returns the hash that identifies the token being minted
+// by gateways on EVM host chains. It is the Keccak256 hash of the mint selector
+// for the asset and destination, such as "BTC/toEthereum".
+func NewMintSelectorHash(asset multichain.Asset, destination multichain.Chain) pack.Bytes32 {
+	return keccak256([]byte(fmt.Sprintf("%v/to%v", asset, destination)))
+}
+
+// NewPHash returns the payload hash of a transaction. It is the Keccak256 hash
+// of the payload that is passed to the recipient when minting.
+func NewPHash(payload []byte) pack.Bytes32 {
+	return keccak256(payload)
+}
+
+// NewNHash returns the nonce hash of a transaction. It uniquely identifies a
+// deposit (or burn), and is the Keccak256 hash of the nonce, the transaction
+// ID, and the transaction index as a 4 byte big-endian integer.
+func NewNHash(nonce pack.Bytes32, txid []byte, txindex uint32) pack.Bytes32 {
+	buf := make([]byte, 0, 32+len(txid)+4)
+	buf = append(buf, nonce[:]...)
+	buf = append(buf, txid...)
+	buf = append(buf, byte(txindex>>24), byte(txindex>>16), byte(txindex>>8), byte(txindex))
+	return keccak256(buf)
+}
+
+// NewGHash returns the gateway hash of a lock-and-mint transaction. It
+// identifies the gateway to which the deposit was sent, and is the Keccak256
+// hash of the payload hash, the selector hash (see NewMintSelectorHash), the
+// recipient, and the nonce. The recipient is the raw address, such as the 20
+// bytes of an EVM address.
+func NewGHash(phash, shash pack.Bytes32, to []byte, nonce pack.Bytes32) pack.Bytes32 {
+	buf := make([]byte, 0, 3*32+len(to))
+	buf = append(buf, phash[:]...)
+	buf = append(buf, shash[:]...)
+	buf = append(buf, to...)
+	buf = append(buf, nonce[:]...)
+	return keccak256(buf)
+}
+
+// NewMintSighash returns the hash that is signed by RenVM to authorise a mint
+// on an EVM host chain. It is computed in the same way as the gateway
+// contracts:
+//
+//	keccak256(abi.encode(phash, amount, shash, to, nhash))
+//
+// where shash is the hash that identifies the token (see NewMintSelectorHash).
+func NewMintSighash(phash pack.Bytes32, amount pack.U256, shash pack.Bytes32, to [20]byte, nhash pack.Bytes32) pack.Bytes32 {
+	buf := make([]byte, 0, 5*32)
+	buf = append(buf, phash[:]...)
+	amountBytes := amount.Bytes32()
+	buf = append(buf, amountBytes[:]...)
+	buf = append(buf, shash[:]...)
+	buf = append(buf, make([]byte, 12)...)
+	buf = append(buf, to[:]...)
+	buf = append(buf, nhash[:]...)
+	return keccak256(buf)
+}
+
+// MintSighash rebuilds the sighash of an executed mint. The phash, nhash, and
+// recipient are taken from the input of the transaction, and the amount is
+// taken from its output. The recipient must be a hex encoded EVM address.
+func (tx Tx) MintSighash() (pack.Bytes32, error) {
+	if !tx.Selector.IsMint() || tx.Selector.Destination() == "" {
+		return pack.Bytes32{}, fmt.Errorf("selector %v does not mint", tx.Selector)
+	}
+	phash, ok := tx.Input.Get("phash").(pack.Bytes32)
+	if !ok {
+		return pack.Bytes32{}, fmt.Errorf("expected input \"phash\" of type %v", pack.Bytes32{}.Type())
+	}
+	nhash, ok := tx.Input.Get("nhash").(pack.Bytes32)
+	if !ok {
+		return pack.Bytes32{}, fmt.Errorf("expected input \"nhash\" of type %v", pack.Bytes32{}.Type())
+	}
+	toStr, ok := tx.Input.Get("to").(pack.String)
+	if !ok {
+		return pack.Bytes32{}, fmt.Errorf("expected input \"to\" of type %v", pack.String("").Type())
+	}
+	to, err := decodeEVMAddress(string(toStr))
+	if err != nil {
+		return pack.Bytes32{}, fmt.Errorf("decoding recipient: %v", err)
+	}
+	output, err := tx.DecodeOutput()
+	if err != nil {
+		return pack.Bytes32{}, err
+	}
+	amount := output.(MintOutput).Amount
+	if amount == (pack.U256{}) {
+		return pack.Bytes32{}, fmt.Errorf("expected output \"amount\" of type %v", pack.U256{}.Type())
+	}
+	shash := NewMintSelectorHash(tx.Selector.Asset(), tx.Selector.Destination())
+	return NewMintSighash(phash, amount, shash, to, nhash), nil
+}
+
+// VerifyMintSignature verifies that the signature in the output of an executed
+// mint was produced by the given secp256k1 public key (in compressed or
+// uncompressed form) over the expected sighash. If the output also contains a
+// sighash, it must be equal to the expected sighash. An error wrapping
+// ErrInvalidMintSignature is returned if verification fails.
+func (tx Tx) VerifyMintSignature(pubKey []byte) error {
+	sighash, err := tx.MintSighash()
+	if err != nil {
+		return err
+	}
+	output, err := tx.DecodeOutput()
+	if err != nil {
+		return err
+	}
+	mint := output.(MintOutput)
+	if mint.Sighash != (pack.Bytes32{}) && mint.Sighash != sighash {
+		return fmt.Errorf("%w: expected sighash %v, got sighash %v", ErrInvalidMintSignature, sighash, mint.Sighash)
+	}
+	return VerifySignature(sighash, mint.Sig, pubKey)
+}
+
+// VerifySignature verifies that a 65 byte secp256k1 signature, in the
+// [R || S || V] form used by EVM chains, was produced by the given public key
+// (in compressed or uncompressed form) over a hash. V can be 0, 1, 27, or 28.
+// An error wrapping ErrInvalidMintSignature is returned if verification fails.
+func VerifySignature(hash pack.Bytes32, sig pack.Bytes65, pubKey []byte) error {
+	expected, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return fmt.Errorf("parsing public key: %v", err)
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return fmt.Errorf("%w: unexpected recovery id %v", ErrInvalidMintSignature, sig[64])
+	}
+	// Convert the signature into the compact form that is expected by btcec.
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+	actual, _, err := btcec.RecoverCompact(btcec.S256(), compact, hash[:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMintSignature, err)
+	}
+	if !bytes.Equal(actual.SerializeCompressed(), expected.SerializeCompressed()) {
+		return fmt.Errorf("%w: signed by a different public key", ErrInvalidMintSignature)
+	}
+	return nil
+}
+
+// decodeEVMAddress decodes a hex encoded EVM address, with or without the "0x"
+// prefix.
+func decodeEVMAddress(str string) ([20]byte, error) {
+	addr := [20]byte{}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(str, "0x"), "0X"))
+	if err != nil {
+		return addr, err
+	}
+	if len(data) != len(addr) {
+		return addr, fmt.Errorf("expected %v bytes, got %v bytes", len(addr), len(data))
+	}
+	copy(addr[:], data)
+	return addr, nil
+}
+
+func keccak256(data []byte) pack.Bytes32 {
+	hash := pack.Bytes32{}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
@@ -0,0 +1,199 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/rand"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+	"golang.org/x/crypto/sha3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mint signatures", func() {
+
+	to := "0x5eb15c3ce8d2a1d2df8e6e6b0b0e7b8f9b7c4a3d"
+
+	sign := func(key *btcec.PrivateKey, hash pack.Bytes32) pack.Bytes65 {
+		compact, err := btcec.SignCompact(btcec.S256(), key, hash[:], false)
+		Expect(err).ToNot(HaveOccurred())
+		sig := pack.Bytes65{}
+		copy(sig[:64], compact[1:])
+		sig[64] = compact[0]
+		return sig
+	}
+
+	// newMint returns an executed mint that has been signed by the key.
+	newMint := func(r *rand.Rand, key *btcec.PrivateKey) tx.Tx {
+		input := txutil.RandomTxInput(r)
+		input.Set("to", pack.String(to))
+		transaction, err := tx.NewTx("BTC/toEthereum", input)
+		Expect(err).ToNot(HaveOccurred())
+		transaction.Output, err = tx.EncodeOutput(tx.MintOutput{Amount: pack.NewU256FromUint64(1000)})
+		Expect(err).ToNot(HaveOccurred())
+
+		sighash, err := transaction.MintSighash()
+		Expect(err).ToNot(HaveOccurred())
+		output := tx.MintOutput{
+			Hash:    pack.Bytes32(transaction.Hash),
+			Amount:  pack.NewU256FromUint64(1000),
+			Sighash: sighash,
+			Sig:     sign(key, sighash),
+			NHash:   input.Get("nhash").(pack.Bytes32),
+			PHash:   input.Get("phash").(pack.Bytes32),
+		}
+		transaction.Output, err = tx.EncodeOutput(output)
+		Expect(err).ToNot(HaveOccurred())
+		return transaction
+	}
+
+	Context("when computing a sighash", func() {
+		It("should hash the ABI encoding of the arguments", func() {
+			phash, nhash := pack.Bytes32{1}, pack.Bytes32{2}
+			amount := pack.NewU256FromUint64(1000)
+			addr := [20]byte{3}
+
+			shash := tx.NewMintSelectorHash(multichain.BTC, multichain.Ethereum)
+			h := sha3.NewLegacyKeccak256()
+			h.Write([]byte("BTC/toEthereum"))
+			Expect(shash[:]).To(Equal(h.Sum(nil)))
+
+			h = sha3.NewLegacyKeccak256()
+			h.Write(phash[:])
+			amountBytes := amount.Bytes32()
+			h.Write(amountBytes[:])
+			h.Write(shash[:])
+			h.Write(make([]byte, 12))
+			h.Write(addr[:])
+			h.Write(nhash[:])
+			sighash := tx.NewMintSighash(phash, amount, shash, addr, nhash)
+			Expect(sighash[:]).To(Equal(h.Sum(nil)))
+		})
+
+		It("should derive the gateway hashes", func() {
+			payload, txid := []byte{1, 2, 3}, []byte{4, 5, 6}
+			nonce, to := pack.Bytes32{7}, []byte{8, 9}
+
+			h := sha3.NewLegacyKeccak256()
+			h.Write(payload)
+			phash := tx.NewPHash(payload)
+			Expect(phash[:]).To(Equal(h.Sum(nil)))
+
+			h = sha3.NewLegacyKeccak256()
+			h.Write(nonce[:])
+			h.Write(txid)
+			h.Write([]byte{0x01, 0x02, 0x03, 0x04})
+			nhash := tx.NewNHash(nonce, txid, 0x01020304)
+			Expect(nhash[:]).To(Equal(h.Sum(nil)))
+
+			shash := tx.NewMintSelectorHash(multichain.BTC, multichain.Ethereum)
+			h = sha3.NewLegacyKeccak256()
+			h.Write(phash[:])
+			h.Write(shash[:])
+			h.Write(to)
+			h.Write(nonce[:])
+			ghash := tx.NewGHash(phash, shash, to, nonce)
+			Expect(ghash[:]).To(Equal(h.Sum(nil)))
+		})
+
+		It("should rebuild the sighash from the inputs and outputs", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			input := txutil.RandomTxInput(r)
+			input.Set("to", pack.String(to))
+			transaction, err := tx.NewTx("BTC/toEthereum", input)
+			Expect(err).ToNot(HaveOccurred())
+			transaction.Output, err = tx.EncodeOutput(tx.MintOutput{Amount: pack.NewU256FromUint64(1000)})
+			Expect(err).ToNot(HaveOccurred())
+
+			addr := [20]byte{}
+			data, err := hex.DecodeString(to[2:])
+			Expect(err).ToNot(HaveOccurred())
+			copy(addr[:], data)
+			sighash, err := transaction.MintSighash()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sighash).To(Equal(tx.NewMintSighash(
+				input.Get("phash").(pack.Bytes32),
+				pack.NewU256FromUint64(1000),
+				tx.NewMintSelectorHash(multichain.BTC, multichain.Ethereum),
+				addr,
+				input.Get("nhash").(pack.Bytes32),
+			)))
+		})
+
+		It("should return an error if the transaction cannot be a mint", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			key, err := btcec.NewPrivateKey(btcec.S256())
+			Expect(err).ToNot(HaveOccurred())
+
+			transaction := newMint(r, key)
+			transaction.Selector = "BTC/fromEthereum"
+			_, err = transaction.MintSighash()
+			Expect(err).To(HaveOccurred())
+
+			transaction = newMint(r, key)
+			transaction.Input.Set("to", pack.String("not an address"))
+			_, err = transaction.MintSighash()
+			Expect(err).To(HaveOccurred())
+
+			transaction = newMint(r, key)
+			transaction.Output = pack.Typed{}
+			_, err = transaction.MintSighash()
+			Expect(errors.Is(err, tx.ErrNoOutput)).To(BeTrue())
+		})
+	})
+
+	Context("when verifying a signature", func() {
+		It("should succeed for the signing key", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 10; i++ {
+				key, err := btcec.NewPrivateKey(btcec.S256())
+				Expect(err).ToNot(HaveOccurred())
+				transaction := newMint(r, key)
+				Expect(transaction.VerifyMintSignature(key.PubKey().SerializeCompressed())).To(Succeed())
+				Expect(transaction.VerifyMintSignature(key.PubKey().SerializeUncompressed())).To(Succeed())
+			}
+		})
+
+		It("should accept recovery ids with and without the offset", func() {
+			key, err := btcec.NewPrivateKey(btcec.S256())
+			Expect(err).ToNot(HaveOccurred())
+			hash := pack.Bytes32{1, 2, 3}
+			sig := sign(key, hash)
+			Expect(tx.VerifySignature(hash, sig, key.PubKey().SerializeCompressed())).To(Succeed())
+			sig[64] -= 27
+			Expect(tx.VerifySignature(hash, sig, key.PubKey().SerializeCompressed())).To(Succeed())
+			sig[64] = 5
+			Expect(errors.Is(tx.VerifySignature(hash, sig, key.PubKey().SerializeCompressed()), tx.ErrInvalidMintSignature)).To(BeTrue())
+		})
+
+		It("should fail for other keys and tampered outputs", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			key, err := btcec.NewPrivateKey(btcec.S256())
+			Expect(err).ToNot(HaveOccurred())
+			other, err := btcec.NewPrivateKey(btcec.S256())
+			Expect(err).ToNot(HaveOccurred())
+
+			transaction := newMint(r, key)
+			err = transaction.VerifyMintSignature(other.PubKey().SerializeCompressed())
+			Expect(errors.Is(err, tx.ErrInvalidMintSignature)).To(BeTrue())
+
+			// Change the amount, so that the sighash in the output is wrong.
+			transaction.Output.Set("amount", pack.NewU256FromUint64(1001))
+			err = transaction.VerifyMintSignature(key.PubKey().SerializeCompressed())
+			Expect(errors.Is(err, tx.ErrInvalidMintSignature)).To(BeTrue())
+
+			// Remove the sighash, so that only the signature is checked.
+			transaction.Output.Set("sighash", pack.Bytes32{})
+			err = transaction.VerifyMintSignature(key.PubKey().SerializeCompressed())
+			Expect(errors.Is(err, tx.ErrInvalidMintSignature)).To(BeTrue())
+
+			Expect(transaction.VerifyMintSignature([]byte{1, 2, 3})).ToNot(Succeed())
+		})
+	})
+})
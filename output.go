@@ -0,0 +1,129 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/pack"
+)
+
+// ErrNoOutput is returned when decoding the output of a transaction that has
+// not been executed.
+var ErrNoOutput = errors.New("tx has no output")
+
+// MintOutput is the output of a transaction that mints an asset to a host
+// chain. This includes lock-and-mint, burn-and-mint, and event-based claim fees
+// transactions.
+type MintOutput struct {
+	// Hash of the transaction.
+	Hash pack.Bytes32 `json:"hash"`
+	// Amount that is minted, after fees.
+	Amount pack.U256 `json:"amount"`
+	// Sighash that is signed by RenVM.
+	Sighash pack.Bytes32 `json:"sighash"`
+	// Sig is the signature over the sighash, which is submitted to the host
+	// chain to mint the asset.
+	Sig pack.Bytes65 `json:"sig"`
+	// NHash is the nonce hash of the transaction.
+	NHash pack.Bytes32 `json:"nhash"`
+	// PHash is the payload hash of the transaction.
+	PHash pack.Bytes32 `json:"phash"`
+	// TxID and TxIndex identify the transaction on the source chain.
+	TxID    pack.Bytes `json:"txid"`
+	TxIndex pack.U32   `json:"txindex"`
+	// Revert is the reason that the transaction was reverted, or empty if it
+	// was not reverted.
+	Revert pack.String `json:"revert"`
+}
+
+// ReleaseOutput is the output of a burn-and-release transaction.
+type ReleaseOutput struct {
+	// Amount that is released, after fees.
+	Amount pack.U256 `json:"amount"`
+	// TxID and TxIndex identify the release transaction on the origin chain.
+	TxID    pack.Bytes `json:"txid"`
+	TxIndex pack.U32   `json:"txindex"`
+	// Revert is the reason that the transaction was reverted, or empty if it
+	// was not reverted.
+	Revert pack.String `json:"revert"`
+}
+
+// ClaimFeesOutput is the output of a transaction that claims fees to the
+// origin chain of an asset.
+type ClaimFeesOutput struct {
+	// Amount of fees that are claimed.
+	Amount pack.U256 `json:"amount"`
+	// TxID and TxIndex identify the claim transaction on the origin chain.
+	TxID    pack.Bytes `json:"txid"`
+	TxIndex pack.U32   `json:"txindex"`
+	// Revert is the reason that the transaction was reverted, or empty if it
+	// was not reverted.
+	Revert pack.String `json:"revert"`
+}
+
+// EncodeOutput encodes a typed output into the untyped output of a
+// transaction.
+func EncodeOutput(output interface{}) (pack.Typed, error) {
+	switch output.(type) {
+	case MintOutput, ReleaseOutput, ClaimFeesOutput:
+	default:
+		return nil, fmt.Errorf("unexpected output of type %T", output)
+	}
+	value, err := pack.Encode(output)
+	if err != nil {
+		return nil, fmt.Errorf("encoding output: %v", err)
+	}
+	return pack.Typed(value.(pack.Struct)), nil
+}
+
+// DecodeOutput returns the typed output of the transaction. It is a
+// ClaimFeesOutput for claim fees transactions, a MintOutput for transactions
+// that mint, and a ReleaseOutput for transactions that release. Fields that
+// are missing from the output are left empty (for example, reverted
+// transactions might only have a revert reason), but fields that have an
+// unexpected type return an error. An error wrapping ErrNoOutput is returned
+// if the transaction has not been executed.
+func (tx Tx) DecodeOutput() (interface{}, error) {
+	if len(tx.Output) == 0 {
+		return nil, fmt.Errorf("%w: hash=%v", ErrNoOutput, tx.Hash)
+	}
+	var output interface{}
+	var err error
+	switch {
+	case tx.Selector.IsClaimFees():
+		v := ClaimFeesOutput{}
+		err = pack.Decode(&v, tx.Output)
+		output = v
+	case tx.Selector.IsMint():
+		v := MintOutput{}
+		err = pack.Decode(&v, tx.Output)
+		output = v
+	case tx.Selector.IsRelease():
+		v := ReleaseOutput{}
+		err = pack.Decode(&v, tx.Output)
+		output = v
+	default:
+		return nil, fmt.Errorf("selector %v has no typed output", tx.Selector)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding output: %v", err)
+	}
+	return output, nil
+}
+
+// IsReverted returns true if the transaction was executed, and its execution
+// was reverted.
+func (tx Tx) IsReverted() bool {
+	return tx.RevertReason() != ""
+}
+
+// RevertReason returns the reason that the execution of the transaction was
+// reverted. It is empty if the transaction was not reverted, or has not been
+// executed.
+func (tx Tx) RevertReason() string {
+	revert, ok := tx.Output.Get("revert").(pack.String)
+	if !ok {
+		return ""
+	}
+	return string(revert)
+}
@@ -0,0 +1,122 @@
+package tx_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outputs", func() {
+
+	mintOutput := tx.MintOutput{
+		Hash:    pack.Bytes32{1},
+		Amount:  pack.NewU256FromUint64(1000),
+		Sighash: pack.Bytes32{2},
+		Sig:     pack.Bytes65{3},
+		NHash:   pack.Bytes32{4},
+		PHash:   pack.Bytes32{5},
+		TxID:    pack.Bytes{6, 7, 8},
+		TxIndex: 1,
+		Revert:  "",
+	}
+	releaseOutput := tx.ReleaseOutput{
+		Amount:  pack.NewU256FromUint64(1000),
+		TxID:    pack.Bytes{6, 7, 8},
+		TxIndex: 2,
+		Revert:  "",
+	}
+	claimFeesOutput := tx.ClaimFeesOutput{
+		Amount:  pack.NewU256FromUint64(1000),
+		TxID:    pack.Bytes{6, 7, 8},
+		TxIndex: 3,
+		Revert:  "",
+	}
+
+	withOutput := func(selector tx.Selector, output interface{}) tx.Tx {
+		r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+		transaction, err := tx.NewTx(selector, txutil.RandomTxInput(r))
+		Expect(err).ToNot(HaveOccurred())
+		transaction.Output, err = tx.EncodeOutput(output)
+		Expect(err).ToNot(HaveOccurred())
+		return transaction
+	}
+
+	Context("when decoding the output of an executed transaction", func() {
+		It("should return the output for the selector", func() {
+			for _, selector := range []tx.Selector{"BTC/toEthereum", "BTC/toSolanaFromEthereum", "BTC/claimFeesFromEvent"} {
+				output, err := withOutput(selector, mintOutput).DecodeOutput()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(output).To(Equal(mintOutput))
+			}
+
+			output, err := withOutput("BTC/fromEthereum", releaseOutput).DecodeOutput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal(releaseOutput))
+
+			output, err = withOutput("BTC/claimFees", claimFeesOutput).DecodeOutput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal(claimFeesOutput))
+		})
+
+		It("should leave missing fields empty", func() {
+			transaction := withOutput("BTC/toEthereum", mintOutput)
+			transaction.Output = pack.NewTyped("revert", pack.String("insufficient amount"))
+			output, err := transaction.DecodeOutput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.(tx.MintOutput).Revert).To(Equal(pack.String("insufficient amount")))
+			Expect(output.(tx.MintOutput).Sig).To(Equal(pack.Bytes65{}))
+		})
+
+		It("should return an error if a field has the wrong type", func() {
+			transaction := withOutput("BTC/fromEthereum", releaseOutput)
+			transaction.Output = pack.NewTyped("txindex", pack.U64(1))
+			_, err := transaction.DecodeOutput()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when decoding the output of a transaction that has not been executed", func() {
+		It("should return an error", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			_, err := txutil.RandomGoodTx(r).DecodeOutput()
+			Expect(errors.Is(err, tx.ErrNoOutput)).To(BeTrue())
+		})
+	})
+
+	Context("when decoding the output of a transaction without a typed output", func() {
+		It("should return an error", func() {
+			transaction := withOutput("BTC/toEthereum", mintOutput)
+			transaction.Selector = "System/epoch"
+			_, err := transaction.DecodeOutput()
+			Expect(err).To(HaveOccurred())
+			_, err = tx.EncodeOutput(tx.EpochInput{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when checking whether a transaction was reverted", func() {
+		It("should return the revert reason", func() {
+			transaction := withOutput("BTC/toEthereum", mintOutput)
+			Expect(transaction.IsReverted()).To(BeFalse())
+			Expect(transaction.RevertReason()).To(BeEmpty())
+
+			reverted := mintOutput
+			reverted.Revert = "nonce already used"
+			transaction = withOutput("BTC/toEthereum", reverted)
+			Expect(transaction.IsReverted()).To(BeTrue())
+			Expect(transaction.RevertReason()).To(Equal("nonce already used"))
+
+			withStatus := tx.WithStatus{Tx: transaction, Status: tx.StatusDone}
+			Expect(withStatus.IsReverted()).To(BeTrue())
+
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			Expect(txutil.RandomGoodTx(r).IsReverted()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,163 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// Errors returned when a transaction does not comply with a policy.
+var (
+	// ErrPayloadTooLarge is returned when the "payload" input of a transaction
+	// is longer than the limit of the policy.
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrTxTooLarge is returned when the encoding of a transaction is larger
+	// than the limit of the policy.
+	ErrTxTooLarge = errors.New("tx too large")
+	// ErrRecipientTooLong is returned when the "to" input of a transaction is
+	// longer than the limit of the policy.
+	ErrRecipientTooLong = errors.New("recipient too long")
+	// ErrUnexpectedInput is returned when a transaction has an input that is
+	// not allowed for its selector by the policy.
+	ErrUnexpectedInput = errors.New("unexpected input")
+)
+
+// Limits of the default policy (see DefaultPolicy).
+const (
+	// MaxPayloadBytes is the maximum length of the "payload" input of a
+	// transaction.
+	MaxPayloadBytes = 64 * 1024
+
+	// MaxRecipientLength is the maximum length of the "to" input of a
+	// transaction. It is much longer than any supported address, so that
+	// addresses of new chains do not need a new policy.
+	MaxRecipientLength = 512
+
+	// MaxTxBytes is the maximum size of the surge encoding of the version,
+	// selector, and input of a transaction.
+	MaxTxBytes = 128 * 1024
+)
+
+// A Policy limits the size and shape of transaction inputs. Unlike Validate, a
+// policy is cheap to check: it never hashes the transaction, so it can be used
+// to reject oversized transactions before they are hashed. DefaultPolicy
+// returns the policy for the network, and NewPolicy returns a policy with
+// custom limits.
+type Policy struct {
+	// MaxPayloadBytes is the maximum length of the "payload" input. Zero
+	// means there is no limit.
+	MaxPayloadBytes int
+	// MaxRecipientLength is the maximum length of the "to" input. Zero means
+	// there is no limit.
+	MaxRecipientLength int
+	// MaxTxBytes is the maximum size of the surge encoding of the version,
+	// selector, and input of the transaction (the data that is hashed), as
+	// estimated by their surge size hints. The hash and output are not
+	// included, because they are not submitted by users. Zero means there is
+	// no limit.
+	MaxTxBytes int
+	// AllowedInputs returns the names of the inputs that are allowed for a
+	// selector, or nil if any inputs are allowed. If it is nil, any inputs are
+	// allowed for all selectors.
+	AllowedInputs func(selector Selector) []string
+}
+
+// DefaultPolicy returns the policy for transactions that are submitted to the
+// network: payloads of at most MaxPayloadBytes, recipients of at most
+// MaxRecipientLength, transactions of at most MaxTxBytes, and the inputs
+// returned by DefaultAllowedInputs.
+func DefaultPolicy() Policy {
+	return NewPolicy(MaxPayloadBytes, MaxRecipientLength, MaxTxBytes)
+}
+
+// NewPolicy returns a policy with the given limits (see Policy), that only
+// allows the inputs returned by DefaultAllowedInputs.
+func NewPolicy(maxPayloadBytes, maxRecipientLength, maxTxBytes int) Policy {
+	return Policy{
+		MaxPayloadBytes:    maxPayloadBytes,
+		MaxRecipientLength: maxRecipientLength,
+		MaxTxBytes:         maxTxBytes,
+		AllowedInputs:      DefaultAllowedInputs,
+	}
+}
+
+// DefaultAllowedInputs returns the inputs of the intrinsic input type for
+// intrinsic selectors, and the inputs of CrossChainInputTypes for lock-and-mint,
+// burn-and-release, and burn-and-mint selectors. Any inputs are allowed for
+// other selectors.
+func DefaultAllowedInputs(selector Selector) []string {
+	var fields pack.Struct
+	switch {
+	case selector.IsIntrinsic():
+		var input interface{}
+		switch selector.Fn() {
+		case SyncWithChainFn:
+			input = SyncWithChainInput{}
+		case ChangeFeesFn:
+			input = ChangeFeesInput{}
+		case EpochFn:
+			input = EpochInput{}
+		case ChangeSignatoriesFn:
+			input = ChangeSignatoriesInput{}
+		}
+		zero, err := pack.Encode(input)
+		if err != nil {
+			return nil
+		}
+		fields = zero.(pack.Struct)
+	case selector.IsLock() || selector.IsBurn():
+		fields = pack.Struct(CrossChainInputTypes())
+	default:
+		return nil
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// CheckPolicy returns an error if the transaction does not comply with the
+// policy. The size of the transaction is checked first, so that oversized
+// transactions are rejected without looking at their inputs.
+func (tx Tx) CheckPolicy(policy Policy) error {
+	if policy.MaxTxBytes > 0 {
+		if size := surge.SizeHintString(string(tx.Version)) + surge.SizeHintString(string(tx.Selector)) + surge.SizeHint(tx.Input); size > policy.MaxTxBytes {
+			return fmt.Errorf("%w: %v bytes exceeds the limit of %v bytes", ErrTxTooLarge, size, policy.MaxTxBytes)
+		}
+	}
+	if policy.MaxPayloadBytes > 0 {
+		if payload, ok := tx.Input.Get("payload").(pack.Bytes); ok && len(payload) > policy.MaxPayloadBytes {
+			return fmt.Errorf("%w: %v bytes exceeds the limit of %v bytes", ErrPayloadTooLarge, len(payload), policy.MaxPayloadBytes)
+		}
+	}
+	if policy.MaxRecipientLength > 0 {
+		if to, ok := tx.Input.Get("to").(pack.String); ok && len(to) > policy.MaxRecipientLength {
+			return fmt.Errorf("%w: %v bytes exceeds the limit of %v bytes", ErrRecipientTooLong, len(to), policy.MaxRecipientLength)
+		}
+	}
+	if policy.AllowedInputs != nil {
+		if allowed := policy.AllowedInputs(tx.Selector); allowed != nil {
+			for _, field := range tx.Input {
+				if !containsString(allowed, field.Name) {
+					sorted := append([]string{}, allowed...)
+					sort.Strings(sorted)
+					return fmt.Errorf("%w %q for selector %v: expected one of %q", ErrUnexpectedInput, field.Name, tx.Selector, sorted)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
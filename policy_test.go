@@ -0,0 +1,156 @@
+package tx_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies", func() {
+
+	policy := tx.DefaultPolicy()
+
+	// withInput returns a copy of a transaction with an input set or added.
+	withInput := func(transaction tx.Tx, name string, value pack.Value) tx.Tx {
+		input := make(pack.Typed, 0, len(transaction.Input)+1)
+		found := false
+		for _, field := range transaction.Input {
+			if field.Name == name {
+				field, found = pack.NewStructField(name, value), true
+			}
+			input = append(input, field)
+		}
+		if !found {
+			input = append(input, pack.NewStructField(name, value))
+		}
+		transaction.Input = input
+		return transaction
+	}
+
+	Context("when checking the default policy", func() {
+		It("should use the network limits", func() {
+			Expect(policy.MaxPayloadBytes).To(Equal(tx.MaxPayloadBytes))
+			Expect(policy.MaxRecipientLength).To(Equal(tx.MaxRecipientLength))
+			Expect(policy.MaxTxBytes).To(Equal(tx.MaxTxBytes))
+		})
+
+		It("should accept good transactions", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				Expect(txutil.RandomGoodTx(r).CheckPolicy(policy)).To(Succeed())
+			}
+		})
+
+		It("should accept valid transactions", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				Expect(txutil.RandomValidTx(r).CheckPolicy(policy)).To(Succeed())
+			}
+		})
+
+		It("should accept intrinsic transactions", func() {
+			transaction, err := tx.NewChangeFeesTx("BTC", tx.ChangeFeesInput{
+				Fees: []tx.ChainFees{{Chain: "Ethereum", UnderlyingFee: pack.NewU256FromUint64(1), MintFee: 15, BurnFee: 15}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.CheckPolicy(policy)).To(Succeed())
+
+			transaction, err = tx.NewEpochTx("BTC", tx.EpochInput{Number: 1, Hash: pack.Bytes32{1}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.CheckPolicy(policy)).To(Succeed())
+			Expect(withInput(transaction, "extra", pack.NewU8(1)).CheckPolicy(policy)).To(MatchError(tx.ErrUnexpectedInput))
+		})
+
+		It("should allow the inputs of each intrinsic type", func() {
+			Expect(tx.DefaultAllowedInputs("BTC/epoch")).To(Equal([]string{"number", "hash"}))
+			Expect(tx.DefaultAllowedInputs("BTC/changeFees")).To(ContainElement("fees"))
+			Expect(tx.DefaultAllowedInputs("BTC/toEthereum")).To(ContainElement("nhash"))
+			Expect(tx.DefaultAllowedInputs("BTC/claimFees")).To(BeNil())
+		})
+
+		It("should reject oversized payloads without hashing", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			err := withInput(transaction, "payload", pack.NewBytes(make([]byte, tx.MaxPayloadBytes+1))).CheckPolicy(policy)
+			Expect(errors.Is(err, tx.ErrPayloadTooLarge)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("65537 bytes"))
+
+			// A very large payload exceeds the limit on the whole transaction.
+			err = withInput(transaction, "payload", pack.NewBytes(make([]byte, 100*1024*1024))).CheckPolicy(policy)
+			Expect(errors.Is(err, tx.ErrTxTooLarge)).To(BeTrue())
+		})
+
+		It("should reject long recipients", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			to := make([]byte, policy.MaxRecipientLength+1)
+			for i := range to {
+				to[i] = 'a'
+			}
+			err := withInput(transaction, "to", pack.String(to)).CheckPolicy(policy)
+			Expect(errors.Is(err, tx.ErrRecipientTooLong)).To(BeTrue())
+		})
+
+		It("should reject unexpected inputs", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			err := withInput(transaction, "extra", pack.NewU8(1)).CheckPolicy(policy)
+			Expect(errors.Is(err, tx.ErrUnexpectedInput)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`"extra"`))
+		})
+	})
+
+	Context("when checking a custom policy", func() {
+		It("should use the given limits", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			transaction = withInput(transaction, "payload", pack.NewBytes(make([]byte, tx.MaxPayloadBytes+1)))
+			Expect(errors.Is(transaction.CheckPolicy(policy), tx.ErrPayloadTooLarge)).To(BeTrue())
+			Expect(transaction.CheckPolicy(tx.NewPolicy(2*tx.MaxPayloadBytes, tx.MaxRecipientLength, tx.MaxTxBytes))).To(Succeed())
+		})
+
+		It("should ignore limits that are zero", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			transaction = withInput(transaction, "payload", pack.NewBytes(make([]byte, 1024*1024)))
+			transaction = withInput(transaction, "extra", pack.NewU8(1))
+			Expect(transaction.CheckPolicy(tx.Policy{})).To(Succeed())
+		})
+
+		It("should use the allowed inputs of the selector", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			policy := tx.Policy{AllowedInputs: func(selector tx.Selector) []string {
+				if selector == transaction.Selector {
+					return []string{"amount"}
+				}
+				return nil
+			}}
+			Expect(errors.Is(transaction.CheckPolicy(policy), tx.ErrUnexpectedInput)).To(BeTrue())
+			Expect(tx.Tx{Selector: transaction.Selector, Input: pack.NewTyped("amount", pack.NewU64(1))}.CheckPolicy(policy)).To(Succeed())
+			Expect(tx.Tx{Selector: "BTC/claimFees", Input: transaction.Input}.CheckPolicy(policy)).To(Succeed())
+		})
+
+		It("should limit the size of the transaction", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			Expect(transaction.CheckPolicy(tx.Policy{MaxTxBytes: 10})).To(MatchError(tx.ErrTxTooLarge))
+		})
+
+		It("should only limit the size of the version, selector, and input", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			size := surge.SizeHintString(string(transaction.Version)) + surge.SizeHintString(string(transaction.Selector)) + surge.SizeHint(transaction.Input)
+			transaction.Output = pack.NewTyped("revert", pack.String(make([]byte, 1024)))
+			Expect(transaction.CheckPolicy(tx.Policy{MaxTxBytes: size})).To(Succeed())
+			Expect(transaction.CheckPolicy(tx.Policy{MaxTxBytes: size - 1})).To(MatchError(tx.ErrTxTooLarge))
+		})
+	})
+})
@@ -0,0 +1,372 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/renproject/multichain"
+)
+
+// A Registry declares the assets, host chains, and routes that are supported
+// on each network. Registries are usually loaded from configuration, so that
+// supporting a new host chain does not require a new release of this package.
+type Registry struct {
+	Networks map[multichain.Network]NetworkRegistry `json:"networks" yaml:"networks"`
+}
+
+// A NetworkRegistry declares the assets, host chains, and routes that are
+// supported on a single network.
+type NetworkRegistry struct {
+	// Assets that can be locked on their origin chain. The origin chain of
+	// every asset must be known.
+	Assets []multichain.Asset `json:"assets" yaml:"assets"`
+
+	// HostChains to which assets can be minted.
+	HostChains []multichain.Chain `json:"hostChains" yaml:"hostChains"`
+
+	// Routes that are allowed. If there are no routes, then all routes between
+	// the origin chains of the assets and the host chains are allowed.
+	Routes []Route `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// A Route is the movement of an asset from one chain to another.
+type Route struct {
+	// Asset that is being moved. If it is empty, the route is allowed for all
+	// assets.
+	Asset multichain.Asset `json:"asset,omitempty" yaml:"asset,omitempty"`
+	From  multichain.Chain `json:"from" yaml:"from"`
+	To    multichain.Chain `json:"to" yaml:"to"`
+}
+
+// DefaultRegistry returns the registry of assets and host chains that are
+// supported by RenVM. All routes are allowed.
+func DefaultRegistry() Registry {
+	assets := []multichain.Asset{
+		multichain.BCH, multichain.BTC, multichain.DGB, multichain.DOGE,
+		multichain.FIL, multichain.LUNA, multichain.ZEC,
+	}
+	mainnetHosts := []multichain.Chain{
+		multichain.Arbitrum, multichain.Avalanche, multichain.BinanceSmartChain,
+		multichain.Ethereum, multichain.Fantom, multichain.Moonbeam,
+		multichain.Polygon, multichain.Solana,
+	}
+	testnetHosts := []multichain.Chain{
+		multichain.Arbitrum, multichain.Avalanche, multichain.BinanceSmartChain,
+		multichain.Ethereum, multichain.Fantom, multichain.Goerli,
+		multichain.Moonbeam, multichain.Polygon, multichain.Solana,
+	}
+	return Registry{
+		Networks: map[multichain.Network]NetworkRegistry{
+			multichain.NetworkMainnet: {Assets: assets, HostChains: mainnetHosts},
+			multichain.NetworkTestnet: {Assets: assets, HostChains: testnetHosts},
+			multichain.NetworkDevnet:  {Assets: assets, HostChains: testnetHosts},
+		},
+	}
+}
+
+// NewRegistryFromJSON returns the registry that is defined by JSON data. Unknown
+// fields are rejected, and the registry is validated.
+func NewRegistryFromJSON(data []byte) (Registry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	registry := Registry{}
+	if err := decoder.Decode(&registry); err != nil {
+		return Registry{}, fmt.Errorf("unmarshaling registry: %v", err)
+	}
+	if err := registry.Validate(); err != nil {
+		return Registry{}, err
+	}
+	return registry, nil
+}
+
+// LoadRegistry reads a JSON registry from a file. To load a YAML registry, use
+// the txyaml package.
+func LoadRegistry(filename string) (Registry, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Registry{}, err
+	}
+	return NewRegistryFromJSON(data)
+}
+
+// Network returns the registry for a network, and whether or not the network is
+// declared.
+func (registry Registry) Network(network multichain.Network) (NetworkRegistry, bool) {
+	networkRegistry, ok := registry.Networks[network]
+	return networkRegistry, ok
+}
+
+// Validate returns an error if any of the networks in the registry are not
+// known, or are not valid.
+func (registry Registry) Validate() error {
+	for network, networkRegistry := range registry.Networks {
+		switch network {
+		case multichain.NetworkMainnet, multichain.NetworkTestnet, multichain.NetworkDevnet, multichain.NetworkLocalnet:
+		default:
+			return fmt.Errorf("unknown network %q", network)
+		}
+		if err := networkRegistry.Validate(); err != nil {
+			return fmt.Errorf("invalid %v registry: %v", network, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if an asset has an unknown origin chain, if an
+// asset or host chain is declared more than once, or if a route refers to an
+// asset or chain that is not declared.
+func (registry NetworkRegistry) Validate() error {
+	chains := map[multichain.Chain]bool{}
+	assets := map[multichain.Asset]bool{}
+	for _, asset := range registry.Assets {
+		if asset.OriginChain() == "" {
+			return fmt.Errorf("unknown asset %q", asset)
+		}
+		if assets[asset] {
+			return fmt.Errorf("duplicate asset %v", asset)
+		}
+		assets[asset] = true
+		chains[asset.OriginChain()] = true
+	}
+	hosts := map[multichain.Chain]bool{}
+	for _, host := range registry.HostChains {
+		if host == "" {
+			return fmt.Errorf("empty host chain")
+		}
+		if hosts[host] {
+			return fmt.Errorf("duplicate host chain %v", host)
+		}
+		hosts[host] = true
+		chains[host] = true
+	}
+	for _, route := range registry.Routes {
+		if route.Asset != "" && !assets[route.Asset] {
+			return fmt.Errorf("route from %v to %v: unknown asset %v", route.From, route.To, route.Asset)
+		}
+		if !chains[route.From] {
+			return fmt.Errorf("route from %v to %v: unknown chain %v", route.From, route.To, route.From)
+		}
+		if !chains[route.To] {
+			return fmt.Errorf("route from %v to %v: unknown chain %v", route.From, route.To, route.To)
+		}
+		if route.From == route.To {
+			return fmt.Errorf("route from %v to %v: same chain", route.From, route.To)
+		}
+	}
+	return nil
+}
+
+// AllowsRoute returns true if the asset is allowed to move from one chain to
+// another.
+func (registry NetworkRegistry) AllowsRoute(asset multichain.Asset, from, to multichain.Chain) bool {
+	if from == to {
+		return false
+	}
+	if len(registry.Routes) == 0 {
+		return true
+	}
+	for _, route := range registry.Routes {
+		if (route.Asset == "" || route.Asset == asset) && route.From == from && route.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSelector returns true if the selector is one of AllSelectors. The
+// selector is parsed and checked directly, without enumerating AllSelectors.
+func (registry NetworkRegistry) AllowsSelector(selector Selector) bool {
+	asset, source, destination := selector.Asset(), selector.Source(), selector.Destination()
+	if !registry.hasAsset(asset) || !registry.AllowsRoute(asset, source, destination) {
+		return false
+	}
+	origin := asset.OriginChain()
+	if source == origin && registry.hasHostChain(destination) && selector == Selector(string(asset)+"/to"+string(destination)) {
+		return true
+	}
+	if destination == origin && registry.hasHostChain(source) && selector == Selector(string(asset)+"/from"+string(source)) {
+		return true
+	}
+	return registry.hasHostChain(source) && registry.hasHostChain(destination) && selector == Selector(string(asset)+"/to"+string(destination)+"From"+string(source))
+}
+
+func (registry NetworkRegistry) hasAsset(asset multichain.Asset) bool {
+	for _, declared := range registry.Assets {
+		if declared == asset {
+			return true
+		}
+	}
+	return false
+}
+
+func (registry NetworkRegistry) hasHostChain(chain multichain.Chain) bool {
+	for _, declared := range registry.HostChains {
+		if declared == chain {
+			return true
+		}
+	}
+	return false
+}
+
+// AllSelectors returns the selectors for all allowed lock-and-mint,
+// burn-and-release, and burn-and-mint routes.
+func (registry NetworkRegistry) AllSelectors() []Selector {
+	selectors := make([]Selector, 0, 2*len(registry.Assets)*len(registry.HostChains))
+	for _, asset := range registry.Assets {
+		for _, host := range registry.HostChains {
+			selectors = registry.appendLock(selectors, asset, host)
+			selectors = registry.appendRelease(selectors, asset, host)
+			for _, otherHost := range registry.HostChains {
+				selectors = registry.appendBurnAndMint(selectors, asset, host, otherHost)
+			}
+		}
+	}
+	return selectors
+}
+
+// SelectorsByAsset returns the selectors for all allowed routes, grouped by the
+// asset that is being moved.
+func (registry NetworkRegistry) SelectorsByAsset() map[multichain.Asset][]Selector {
+	selectors := make(map[multichain.Asset][]Selector)
+	for _, asset := range registry.Assets {
+		selectors[asset] = make([]Selector, 0, 2*len(registry.HostChains))
+		for _, host := range registry.HostChains {
+			selectors[asset] = registry.appendLock(selectors[asset], asset, host)
+			selectors[asset] = registry.appendRelease(selectors[asset], asset, host)
+			for _, otherHost := range registry.HostChains {
+				selectors[asset] = registry.appendBurnAndMint(selectors[asset], asset, host, otherHost)
+			}
+		}
+	}
+	return selectors
+}
+
+// SelectorsByDestination returns the selectors for all allowed routes, grouped
+// by the chain to which assets are moving.
+func (registry NetworkRegistry) SelectorsByDestination() map[multichain.Chain][]Selector {
+	selectors := make(map[multichain.Chain][]Selector)
+
+	// Populate asset/toHost selectors.
+	for _, host := range registry.HostChains {
+		for _, asset := range registry.Assets {
+			selectors[host] = registry.appendLock(selectors[host], asset, host)
+		}
+	}
+
+	// Populate asset/fromHost selectors.
+	for _, asset := range registry.Assets {
+		assetChain := asset.OriginChain()
+		for _, host := range registry.HostChains {
+			selectors[assetChain] = registry.appendRelease(selectors[assetChain], asset, host)
+		}
+	}
+
+	// Populate asset/toHostFromOtherHost selectors.
+	for _, host := range registry.HostChains {
+		for _, otherHost := range registry.HostChains {
+			for _, asset := range registry.Assets {
+				selectors[host] = registry.appendBurnAndMint(selectors[host], asset, host, otherHost)
+			}
+		}
+	}
+
+	return selectors
+}
+
+// SelectorsBySource returns the selectors for all allowed routes, grouped by
+// the chain from which assets are moving.
+func (registry NetworkRegistry) SelectorsBySource() map[multichain.Chain][]Selector {
+	selectors := make(map[multichain.Chain][]Selector)
+
+	// Populate asset/toHost selectors.
+	for _, asset := range registry.Assets {
+		assetChain := asset.OriginChain()
+		for _, host := range registry.HostChains {
+			selectors[assetChain] = registry.appendLock(selectors[assetChain], asset, host)
+		}
+	}
+
+	// Populate asset/fromHost selectors.
+	for _, host := range registry.HostChains {
+		for _, asset := range registry.Assets {
+			selectors[host] = registry.appendRelease(selectors[host], asset, host)
+		}
+	}
+
+	// Populate asset/toHostFromOtherHost selectors.
+	for _, host := range registry.HostChains {
+		for _, otherHost := range registry.HostChains {
+			for _, asset := range registry.Assets {
+				selectors[host] = registry.appendBurnAndMint(selectors[host], asset, otherHost, host)
+			}
+		}
+	}
+
+	return selectors
+}
+
+// LockSelectors returns the selectors for all allowed lock-and-mint routes,
+// grouped by the chain on which assets are locked.
+func (registry NetworkRegistry) LockSelectors() map[multichain.Chain][]Selector {
+	selectors := make(map[multichain.Chain][]Selector)
+	for _, asset := range registry.Assets {
+		assetChain := asset.OriginChain()
+		for _, host := range registry.HostChains {
+			selectors[assetChain] = registry.appendLock(selectors[assetChain], asset, host)
+		}
+	}
+	return selectors
+}
+
+// NonLockSelectors returns the selectors for all allowed burn-and-release and
+// burn-and-mint routes, grouped by the origin chain of the asset.
+func (registry NetworkRegistry) NonLockSelectors() map[multichain.Chain][]Selector {
+	selectors := make(map[multichain.Chain][]Selector)
+
+	// Populate asset/fromHost selectors.
+	for _, asset := range registry.Assets {
+		assetChain := asset.OriginChain()
+		for _, host := range registry.HostChains {
+			selectors[assetChain] = registry.appendRelease(selectors[assetChain], asset, host)
+		}
+	}
+
+	// Populate asset/toHostFromOtherHost selectors.
+	for _, asset := range registry.Assets {
+		assetChain := asset.OriginChain()
+		for _, host := range registry.HostChains {
+			for _, otherHost := range registry.HostChains {
+				selectors[assetChain] = registry.appendBurnAndMint(selectors[assetChain], asset, host, otherHost)
+			}
+		}
+	}
+
+	return selectors
+}
+
+// appendLock appends the "asset/toHost" selector, if the route is allowed.
+func (registry NetworkRegistry) appendLock(selectors []Selector, asset multichain.Asset, host multichain.Chain) []Selector {
+	if !registry.AllowsRoute(asset, asset.OriginChain(), host) {
+		return selectors
+	}
+	return append(selectors, Selector(fmt.Sprintf("%v/to%v", asset, host)))
+}
+
+// appendRelease appends the "asset/fromHost" selector, if the route is allowed.
+func (registry NetworkRegistry) appendRelease(selectors []Selector, asset multichain.Asset, host multichain.Chain) []Selector {
+	if !registry.AllowsRoute(asset, host, asset.OriginChain()) {
+		return selectors
+	}
+	return append(selectors, Selector(fmt.Sprintf("%v/from%v", asset, host)))
+}
+
+// appendBurnAndMint appends the "asset/toHostFromOtherHost" selector, if the
+// route is allowed.
+func (registry NetworkRegistry) appendBurnAndMint(selectors []Selector, asset multichain.Asset, host, otherHost multichain.Chain) []Selector {
+	if !registry.AllowsRoute(asset, otherHost, host) {
+		return selectors
+	}
+	return append(selectors, Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, otherHost)))
+}
@@ -0,0 +1,187 @@
+package tx_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry", func() {
+
+	registryJSON := `{
+		"networks": {
+			"mainnet": {
+				"assets": ["BTC", "ZEC"],
+				"hostChains": ["Ethereum", "Solana"],
+				"routes": [
+					{"from": "Bitcoin", "to": "Ethereum"},
+					{"from": "Ethereum", "to": "Bitcoin"},
+					{"asset": "ZEC", "from": "Zcash", "to": "Solana"},
+					{"asset": "BTC", "from": "Ethereum", "to": "Solana"}
+				]
+			},
+			"testnet": {
+				"assets": ["BTC", "ZEC"],
+				"hostChains": ["Ethereum", "Goerli", "Solana"]
+			}
+		}
+	}`
+
+	Context("when loading a registry", func() {
+		It("should load the registry from JSON", func() {
+			fromJSON, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+
+			mainnet, ok := fromJSON.Network(multichain.NetworkMainnet)
+			Expect(ok).To(BeTrue())
+			Expect(mainnet.Assets).To(Equal([]multichain.Asset{multichain.BTC, multichain.ZEC}))
+			Expect(mainnet.Routes).To(HaveLen(4))
+			_, ok = fromJSON.Network(multichain.NetworkDevnet)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should load a registry from a file", func() {
+			dir, err := ioutil.TempDir("", "registry")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "registry.json"), []byte(registryJSON), 0644)).To(Succeed())
+			fromFile, err := tx.LoadRegistry(filepath.Join(dir, "registry.json"))
+			Expect(err).ToNot(HaveOccurred())
+			fromJSON, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fromFile).To(Equal(fromJSON))
+
+			_, err = tx.LoadRegistry(filepath.Join(dir, "missing.json"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should marshal and unmarshal the default registry", func() {
+			data, err := json.Marshal(tx.DefaultRegistry())
+			Expect(err).ToNot(HaveOccurred())
+			registry, err := tx.NewRegistryFromJSON(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(registry).To(Equal(tx.DefaultRegistry()))
+		})
+	})
+
+	Context("when the registry is invalid", func() {
+		It("should return an error", func() {
+			for _, data := range []string{
+				`{"networks": {"betanet": {"assets": ["BTC"], "hostChains": ["Ethereum"]}}}`,
+				`{"networks": {"mainnet": {"assets": ["XYZ"], "hostChains": ["Ethereum"]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC", "BTC"], "hostChains": ["Ethereum"]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": ["Ethereum", "Ethereum"]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": [""]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": ["Ethereum"], "routes": [{"asset": "ZEC", "from": "Bitcoin", "to": "Ethereum"}]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": ["Ethereum"], "routes": [{"from": "Bitcoin", "to": "Solana"}]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": ["Ethereum"], "routes": [{"from": "Ethereum", "to": "Ethereum"}]}}}`,
+				`{"networks": {"mainnet": {"assets": ["BTC"], "hostChains": ["Ethereum"], "unknown": true}}}`,
+			} {
+				_, err := tx.NewRegistryFromJSON([]byte(data))
+				Expect(err).To(HaveOccurred(), data)
+			}
+		})
+	})
+
+	Context("when enumerating selectors", func() {
+		It("should only return selectors for allowed routes", func() {
+			registry, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+			mainnet, _ := registry.Network(multichain.NetworkMainnet)
+
+			Expect(mainnet.AllSelectors()).To(ConsistOf(
+				tx.Selector("BTC/toEthereum"),
+				tx.Selector("BTC/fromEthereum"),
+				tx.Selector("BTC/toSolanaFromEthereum"),
+				tx.Selector("ZEC/toSolana"),
+			))
+			Expect(mainnet.SelectorsByAsset()[multichain.ZEC]).To(ConsistOf(tx.Selector("ZEC/toSolana")))
+			Expect(mainnet.SelectorsByDestination()[multichain.Solana]).To(ConsistOf(
+				tx.Selector("BTC/toSolanaFromEthereum"),
+				tx.Selector("ZEC/toSolana"),
+			))
+			Expect(mainnet.SelectorsBySource()[multichain.Ethereum]).To(ConsistOf(
+				tx.Selector("BTC/fromEthereum"),
+				tx.Selector("BTC/toSolanaFromEthereum"),
+			))
+			Expect(mainnet.LockSelectors()[multichain.Bitcoin]).To(ConsistOf(tx.Selector("BTC/toEthereum")))
+			Expect(mainnet.NonLockSelectors()[multichain.Bitcoin]).To(ConsistOf(
+				tx.Selector("BTC/fromEthereum"),
+				tx.Selector("BTC/toSolanaFromEthereum"),
+			))
+
+			Expect(mainnet.AllowsRoute(multichain.BTC, multichain.Bitcoin, multichain.Ethereum)).To(BeTrue())
+			Expect(mainnet.AllowsRoute(multichain.ZEC, multichain.Zcash, multichain.Ethereum)).To(BeFalse())
+			Expect(mainnet.AllowsRoute(multichain.ZEC, multichain.Ethereum, multichain.Solana)).To(BeFalse())
+		})
+
+		It("should return selectors for all routes when no routes are declared", func() {
+			registry, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+			testnet, _ := registry.Network(multichain.NetworkTestnet)
+
+			// Each asset can be locked to, and released from, each host.
+			// Each asset can also be burned on each host and minted to every
+			// other host.
+			Expect(testnet.AllSelectors()).To(HaveLen(2 * (2*3 + 3*2)))
+			for _, selector := range testnet.AllSelectors() {
+				Expect(selector.Source()).ToNot(Equal(selector.Destination()))
+				Expect(testnet.AllowsRoute(selector.Asset(), selector.Source(), selector.Destination())).To(BeTrue())
+			}
+		})
+
+		It("should only allow the enumerated selectors", func() {
+			registry, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+			mainnet, _ := registry.Network(multichain.NetworkMainnet)
+			testnet, _ := registry.Network(multichain.NetworkTestnet)
+			for _, networkRegistry := range []tx.NetworkRegistry{mainnet, testnet, txutil.DefaultNetworkRegistry()} {
+				enumerated := map[tx.Selector]bool{}
+				for _, selector := range networkRegistry.AllSelectors() {
+					enumerated[selector] = true
+				}
+
+				assets := append([]multichain.Asset{"XYZ"}, networkRegistry.Assets...)
+				chains := append([]multichain.Chain{"Unknown"}, networkRegistry.HostChains...)
+				for _, asset := range networkRegistry.Assets {
+					chains = append(chains, asset.OriginChain())
+				}
+				selectors := []tx.Selector{"", "BTC", "BTC/", "BTC/to", "BTC/toEthereum/", "BTC/toEthereumFrom", "ClaimFees", "BTC/claimFees"}
+				for _, asset := range assets {
+					for _, chain := range chains {
+						selectors = append(selectors,
+							tx.Selector(fmt.Sprintf("%v/to%v", asset, chain)),
+							tx.Selector(fmt.Sprintf("%v/from%v", asset, chain)),
+							tx.Selector(fmt.Sprintf("%v/to%vx", asset, chain)))
+						for _, otherChain := range chains {
+							selectors = append(selectors, tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, chain, otherChain)))
+						}
+					}
+				}
+				for _, selector := range selectors {
+					Expect(networkRegistry.AllowsSelector(selector)).To(Equal(enumerated[selector]), string(selector))
+				}
+			}
+		})
+
+		It("should derive the txutil enumerations from the default registry", func() {
+			testnet, ok := tx.DefaultRegistry().Network(multichain.NetworkTestnet)
+			Expect(ok).To(BeTrue())
+			Expect(txutil.SupportedAssets()).To(Equal(testnet.Assets))
+			Expect(txutil.SupportedHostChains()).To(Equal(testnet.HostChains))
+			Expect(txutil.AllSelectors()).To(Equal(testnet.AllSelectors()))
+			Expect(txutil.SelectorsByAsset()).To(Equal(testnet.SelectorsByAsset()))
+			Expect(txutil.LockSelectors()).To(Equal(testnet.LockSelectors()))
+		})
+	})
+})
@@ -0,0 +1,340 @@
+package tx
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/renproject/multichain"
+)
+
+// A RouteGraph is a directed graph of the chains between which assets can be
+// moved. Every edge is a selector that moves an asset from one chain to
+// another: lock-and-mint edges go from the origin chain of the asset to a host
+// chain, burn-and-release edges go from a host chain to the origin chain, and
+// burn-and-mint edges go from one host chain to another. Edges can be disabled
+// (for example, when a host chain is halted) without rebuilding the graph.
+//
+// A RouteGraph is not safe for concurrent use if edges are being enabled or
+// disabled.
+type RouteGraph struct {
+	edges    map[Route]Selector
+	out      map[multichain.Asset]map[multichain.Chain][]Route
+	disabled map[Route]bool
+}
+
+// NewRouteGraph returns a graph that has an edge for every selector that is
+// allowed by the registry. All edges are enabled.
+func NewRouteGraph(registry NetworkRegistry) *RouteGraph {
+	graph := &RouteGraph{
+		edges:    map[Route]Selector{},
+		out:      map[multichain.Asset]map[multichain.Chain][]Route{},
+		disabled: map[Route]bool{},
+	}
+	for _, selector := range registry.AllSelectors() {
+		edge := routeOf(selector)
+		graph.edges[edge] = selector
+		if graph.out[edge.Asset] == nil {
+			graph.out[edge.Asset] = map[multichain.Chain][]Route{}
+		}
+		graph.out[edge.Asset][edge.From] = append(graph.out[edge.Asset][edge.From], edge)
+	}
+	// Sort outgoing edges so that traversals are deterministic.
+	for _, out := range graph.out {
+		for _, edges := range out {
+			sort.Slice(edges, func(i, j int) bool { return edges[i].To < edges[j].To })
+		}
+	}
+	return graph
+}
+
+// Selectors returns the selectors of all enabled edges, in sorted order.
+func (graph *RouteGraph) Selectors() []Selector {
+	selectors := make([]Selector, 0, len(graph.edges))
+	for edge, selector := range graph.edges {
+		if !graph.disabled[edge] {
+			selectors = append(selectors, selector)
+		}
+	}
+	sort.Slice(selectors, func(i, j int) bool { return selectors[i] < selectors[j] })
+	return selectors
+}
+
+// SelectorForRoute returns the selector that moves the asset directly from one
+// chain to another. It returns false if there is no such edge, or if the edge
+// is disabled.
+func (graph *RouteGraph) SelectorForRoute(asset multichain.Asset, from, to multichain.Chain) (Selector, bool) {
+	edge := Route{Asset: asset, From: from, To: to}
+	selector, ok := graph.edges[edge]
+	if !ok || graph.disabled[edge] {
+		return "", false
+	}
+	return selector, true
+}
+
+// MaxRouteHops is the default maximum number of steps in a route. When all
+// routes are allowed, every pair of chains is connected by at most two steps
+// (a direct step, or a step through the origin chain), so longer routes are
+// rarely useful, and the number of routes grows exponentially with their
+// length.
+const MaxRouteHops = 3
+
+// Routes returns the paths along which the asset can be moved from one chain
+// to another in at most maxHops steps, using only enabled edges. Each path is
+// the sequence of selectors that must be executed, and no path visits the same
+// chain twice. Paths with fewer hops are returned first. If maxHops is zero or
+// negative, MaxRouteHops is used.
+func (graph *RouteGraph) Routes(asset multichain.Asset, from, to multichain.Chain, maxHops int) [][]Selector {
+	if maxHops <= 0 {
+		maxHops = MaxRouteHops
+	}
+	routes := [][]Selector{}
+	if from == to {
+		return routes
+	}
+	visited := map[multichain.Chain]bool{from: true}
+	path := []Selector{}
+	var visit func(chain multichain.Chain)
+	visit = func(chain multichain.Chain) {
+		for _, edge := range graph.out[asset][chain] {
+			if graph.disabled[edge] || visited[edge.To] {
+				continue
+			}
+			path = append(path, graph.edges[edge])
+			if edge.To == to {
+				routes = append(routes, append([]Selector{}, path...))
+			} else if len(path) < maxHops {
+				visited[edge.To] = true
+				visit(edge.To)
+				visited[edge.To] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	visit(from)
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i]) < len(routes[j]) })
+	return routes
+}
+
+// ReachableChains returns the chains to which the asset can be moved from its
+// origin chain, in any number of hops, using only enabled edges. The origin
+// chain is not included. Chains are returned in sorted order.
+func (graph *RouteGraph) ReachableChains(asset multichain.Asset) []multichain.Chain {
+	origin := asset.OriginChain()
+	visited := map[multichain.Chain]bool{origin: true}
+	queue := []multichain.Chain{origin}
+	chains := []multichain.Chain{}
+	for len(queue) > 0 {
+		chain := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph.out[asset][chain] {
+			if graph.disabled[edge] || visited[edge.To] {
+				continue
+			}
+			visited[edge.To] = true
+			queue = append(queue, edge.To)
+			chains = append(chains, edge.To)
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	return chains
+}
+
+// Disable the edge for a selector. Disabled edges are ignored by all
+// traversals until they are enabled again. An error is returned if the
+// selector is not an edge in the graph.
+func (graph *RouteGraph) Disable(selector Selector) error {
+	edge, ok := graph.edge(selector)
+	if !ok {
+		return fmt.Errorf("unknown route %v", selector)
+	}
+	graph.disabled[edge] = true
+	return nil
+}
+
+// Enable the edge for a selector. An error is returned if the selector is not
+// an edge in the graph.
+func (graph *RouteGraph) Enable(selector Selector) error {
+	edge, ok := graph.edge(selector)
+	if !ok {
+		return fmt.Errorf("unknown route %v", selector)
+	}
+	delete(graph.disabled, edge)
+	return nil
+}
+
+// IsEnabled returns true if the selector is an edge in the graph, and the edge
+// is not disabled.
+func (graph *RouteGraph) IsEnabled(selector Selector) bool {
+	edge, ok := graph.edge(selector)
+	return ok && !graph.disabled[edge]
+}
+
+// edge returns the edge for a selector, and whether or not it is in the graph.
+func (graph *RouteGraph) edge(selector Selector) (Route, bool) {
+	edge := routeOf(selector)
+	other, ok := graph.edges[edge]
+	return edge, ok && other == selector
+}
+
+// routeOf returns the route along which a selector moves its asset.
+func routeOf(selector Selector) Route {
+	return Route{Asset: selector.Asset(), From: selector.Source(), To: selector.Destination()}
+}
+
+// A RouteStep is a single hop in a route plan.
+type RouteStep struct {
+	Selector    Selector         `json:"selector"`
+	Source      multichain.Chain `json:"source"`
+	Destination multichain.Chain `json:"destination"`
+}
+
+// A RoutePlan is the sequence of steps that moves an asset from one chain to
+// another. The destination of each step is the source of the next step.
+type RoutePlan struct {
+	Asset multichain.Asset `json:"asset"`
+	Steps []RouteStep      `json:"steps"`
+	Cost  float64          `json:"cost"`
+}
+
+// A RouteCostFunc returns the cost of a step. The cost of a plan is the sum of
+// the costs of its steps.
+type RouteCostFunc func(asset multichain.Asset, step RouteStep) float64
+
+// HopCost is a RouteCostFunc that gives every step a cost of one, so that
+// plans with fewer hops are preferred.
+func HopCost(multichain.Asset, RouteStep) float64 {
+	return 1
+}
+
+// PlanRoutes returns the plans with the lowest cost for moving the asset from
+// one chain to another in at most MaxRouteHops steps, using only enabled
+// edges. Plans are ranked by their cost, then by their number of steps, and at
+// most limit plans are returned (or all plans, if limit is zero or negative).
+// If the cost function is nil, then HopCost is used. An error is returned if
+// there are no paths, or if the cost of a step is negative or NaN.
+//
+// Plans are found by a best-first search of the paths from the source chain,
+// so only the paths that are cheaper than the returned plans are explored,
+// instead of every path.
+func (graph *RouteGraph) PlanRoutes(asset multichain.Asset, from, to multichain.Chain, cost RouteCostFunc, limit int) ([]RoutePlan, error) {
+	if cost == nil {
+		cost = HopCost
+	}
+	plans := []RoutePlan{}
+	if from != to {
+		queue := &routePlanQueue{{plan: RoutePlan{Asset: asset}, chains: []multichain.Chain{from}}}
+		for queue.Len() > 0 && (limit <= 0 || len(plans) < limit) {
+			partial := heap.Pop(queue).(routePlanQueueItem)
+			chain := partial.chains[len(partial.chains)-1]
+			if chain == to {
+				plans = append(plans, partial.plan)
+				continue
+			}
+			if len(partial.plan.Steps) >= MaxRouteHops {
+				continue
+			}
+			for _, edge := range graph.out[asset][chain] {
+				if graph.disabled[edge] || containsChain(partial.chains, edge.To) {
+					continue
+				}
+				step := RouteStep{Selector: graph.edges[edge], Source: edge.From, Destination: edge.To}
+				stepCost := cost(asset, step)
+				if stepCost < 0 || math.IsNaN(stepCost) {
+					return nil, fmt.Errorf("invalid cost %v for step %v", stepCost, step.Selector)
+				}
+				next := routePlanQueueItem{
+					plan: RoutePlan{
+						Asset: asset,
+						Steps: append(append(make([]RouteStep, 0, len(partial.plan.Steps)+1), partial.plan.Steps...), step),
+						Cost:  partial.plan.Cost + stepCost,
+					},
+					chains: append(append(make([]multichain.Chain, 0, len(partial.chains)+1), partial.chains...), edge.To),
+				}
+				heap.Push(queue, next)
+			}
+		}
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("no route for %v from %v to %v", asset, from, to)
+	}
+	return plans, nil
+}
+
+// PlanRoute returns the plan with the lowest cost for moving the asset from one
+// chain to another. If the cost function is nil, then the plan with the fewest
+// steps is returned.
+func (graph *RouteGraph) PlanRoute(asset multichain.Asset, from, to multichain.Chain, cost RouteCostFunc) (RoutePlan, error) {
+	plans, err := graph.PlanRoutes(asset, from, to, cost, 1)
+	if err != nil {
+		return RoutePlan{}, err
+	}
+	return plans[0], nil
+}
+
+// A routePlanQueueItem is a partial plan, and the chains that it visits.
+type routePlanQueueItem struct {
+	plan   RoutePlan
+	chains []multichain.Chain
+}
+
+// A routePlanQueue is a priority queue of partial plans. Plans are ordered by
+// their cost, then by their number of steps, and then by the chains that they
+// visit, so that the order is deterministic. Extending a plan never moves it
+// forward in this order (because costs are not negative), so complete plans
+// are popped in order.
+type routePlanQueue []routePlanQueueItem
+
+func (queue routePlanQueue) Len() int {
+	return len(queue)
+}
+
+func (queue routePlanQueue) Less(i, j int) bool {
+	a, b := queue[i], queue[j]
+	if a.plan.Cost != b.plan.Cost {
+		return a.plan.Cost < b.plan.Cost
+	}
+	if len(a.chains) != len(b.chains) {
+		return len(a.chains) < len(b.chains)
+	}
+	for k := range a.chains {
+		if a.chains[k] != b.chains[k] {
+			return a.chains[k] < b.chains[k]
+		}
+	}
+	return false
+}
+
+func (queue routePlanQueue) Swap(i, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+}
+
+func (queue *routePlanQueue) Push(x interface{}) {
+	*queue = append(*queue, x.(routePlanQueueItem))
+}
+
+func (queue *routePlanQueue) Pop() interface{} {
+	old := *queue
+	item := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return item
+}
+
+func containsChain(chains []multichain.Chain, chain multichain.Chain) bool {
+	for _, c := range chains {
+		if c == chain {
+			return true
+		}
+	}
+	return false
+}
+
+// Selectors returns the selectors of the steps in the plan, in order.
+func (plan RoutePlan) Selectors() []Selector {
+	selectors := make([]Selector, len(plan.Steps))
+	for i, step := range plan.Steps {
+		selectors[i] = step.Selector
+	}
+	return selectors
+}
@@ -0,0 +1,267 @@
+package tx_test
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route graph", func() {
+
+	registry := tx.NetworkRegistry{
+		Assets:     []multichain.Asset{multichain.BTC, multichain.ZEC},
+		HostChains: []multichain.Chain{multichain.Ethereum, multichain.Polygon, multichain.Solana},
+	}
+
+	Context("when building a graph from a registry", func() {
+		It("should have an edge for every selector", func() {
+			graph := tx.NewRouteGraph(txutil.DefaultNetworkRegistry())
+			Expect(graph.Selectors()).To(ConsistOf(txutil.AllSelectors()))
+			for _, selector := range txutil.AllSelectors() {
+				Expect(graph.IsEnabled(selector)).To(BeTrue())
+				other, ok := graph.SelectorForRoute(selector.Asset(), selector.Source(), selector.Destination())
+				Expect(ok).To(BeTrue())
+				Expect(other).To(Equal(selector))
+			}
+		})
+
+		It("should only have edges for allowed routes", func() {
+			registry := registry
+			registry.Routes = []tx.Route{{From: multichain.Bitcoin, To: multichain.Ethereum}}
+			graph := tx.NewRouteGraph(registry)
+			Expect(graph.Selectors()).To(Equal([]tx.Selector{"BTC/toEthereum"}))
+			_, ok := graph.SelectorForRoute(multichain.BTC, multichain.Ethereum, multichain.Bitcoin)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when enumerating routes", func() {
+		It("should return every path without cycles, shortest first", func() {
+			graph := tx.NewRouteGraph(registry)
+			routes := graph.Routes(multichain.BTC, multichain.Bitcoin, multichain.Solana, tx.MaxRouteHops)
+			Expect(routes).To(Equal([][]tx.Selector{
+				{"BTC/toSolana"},
+				{"BTC/toEthereum", "BTC/toSolanaFromEthereum"},
+				{"BTC/toPolygon", "BTC/toSolanaFromPolygon"},
+				{"BTC/toEthereum", "BTC/toPolygonFromEthereum", "BTC/toSolanaFromPolygon"},
+				{"BTC/toPolygon", "BTC/toEthereumFromPolygon", "BTC/toSolanaFromEthereum"},
+			}))
+
+			Expect(graph.Routes(multichain.BTC, multichain.Solana, multichain.Solana, tx.MaxRouteHops)).To(BeEmpty())
+			Expect(graph.Routes(multichain.BTC, multichain.Zcash, multichain.Solana, tx.MaxRouteHops)).To(BeEmpty())
+			Expect(graph.Routes(multichain.DOGE, multichain.Dogecoin, multichain.Solana, tx.MaxRouteHops)).To(BeEmpty())
+		})
+
+		It("should not return paths with more than the maximum number of hops", func() {
+			graph := tx.NewRouteGraph(registry)
+			Expect(graph.Routes(multichain.BTC, multichain.Bitcoin, multichain.Solana, 1)).To(Equal([][]tx.Selector{
+				{"BTC/toSolana"},
+			}))
+			Expect(graph.Routes(multichain.BTC, multichain.Bitcoin, multichain.Solana, 2)).To(HaveLen(3))
+
+			// The default registry has enough host chains that enumerating
+			// every path would be infeasible.
+			graph = tx.NewRouteGraph(txutil.DefaultNetworkRegistry())
+			routes := graph.Routes(multichain.BTC, multichain.Ethereum, multichain.Solana, 0)
+			Expect(routes[0]).To(Equal([]tx.Selector{"BTC/toSolanaFromEthereum"}))
+			for _, route := range routes {
+				Expect(len(route)).To(BeNumerically("<=", tx.MaxRouteHops))
+			}
+		})
+	})
+
+	Context("when finding reachable chains", func() {
+		It("should return all chains that can be reached from the origin chain", func() {
+			graph := tx.NewRouteGraph(registry)
+			Expect(graph.ReachableChains(multichain.BTC)).To(Equal([]multichain.Chain{
+				multichain.Ethereum, multichain.Polygon, multichain.Solana,
+			}))
+			Expect(graph.ReachableChains(multichain.DOGE)).To(BeEmpty())
+		})
+	})
+
+	Context("when disabling edges", func() {
+		It("should ignore the disabled edges until they are enabled", func() {
+			graph := tx.NewRouteGraph(registry)
+			for _, selector := range []tx.Selector{"BTC/toSolana", "BTC/toSolanaFromEthereum", "BTC/toSolanaFromPolygon"} {
+				Expect(graph.Disable(selector)).To(Succeed())
+				Expect(graph.IsEnabled(selector)).To(BeFalse())
+			}
+			_, ok := graph.SelectorForRoute(multichain.BTC, multichain.Bitcoin, multichain.Solana)
+			Expect(ok).To(BeFalse())
+			Expect(graph.Routes(multichain.BTC, multichain.Bitcoin, multichain.Solana, tx.MaxRouteHops)).To(BeEmpty())
+			Expect(graph.ReachableChains(multichain.BTC)).To(Equal([]multichain.Chain{
+				multichain.Ethereum, multichain.Polygon,
+			}))
+			Expect(graph.Selectors()).ToNot(ContainElement(tx.Selector("BTC/toSolana")))
+
+			// Other assets are not affected.
+			Expect(graph.ReachableChains(multichain.ZEC)).To(ContainElement(multichain.Solana))
+
+			Expect(graph.Enable("BTC/toSolanaFromEthereum")).To(Succeed())
+			Expect(graph.Routes(multichain.BTC, multichain.Bitcoin, multichain.Solana, tx.MaxRouteHops)).To(Equal([][]tx.Selector{
+				{"BTC/toEthereum", "BTC/toSolanaFromEthereum"},
+				{"BTC/toPolygon", "BTC/toEthereumFromPolygon", "BTC/toSolanaFromEthereum"},
+			}))
+		})
+
+		It("should return an error for unknown selectors", func() {
+			graph := tx.NewRouteGraph(registry)
+			Expect(graph.Disable("")).ToNot(Succeed())
+			Expect(graph.Disable("BTC/toGoerli")).ToNot(Succeed())
+			Expect(graph.Enable("DOGE/toEthereum")).ToNot(Succeed())
+			Expect(graph.IsEnabled("BTC/toGoerli")).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Route planning", func() {
+
+	registry := tx.NetworkRegistry{
+		Assets:     []multichain.Asset{multichain.BTC},
+		HostChains: []multichain.Chain{multichain.Ethereum, multichain.Polygon, multichain.Solana},
+	}
+
+	Context("when there is a direct route", func() {
+		It("should plan a single step", func() {
+			graph := tx.NewRouteGraph(registry)
+			plan, err := graph.PlanRoute(multichain.BTC, multichain.Solana, multichain.Polygon, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan).To(Equal(tx.RoutePlan{
+				Asset: multichain.BTC,
+				Steps: []tx.RouteStep{
+					{Selector: "BTC/toPolygonFromSolana", Source: multichain.Solana, Destination: multichain.Polygon},
+				},
+				Cost: 1,
+			}))
+		})
+	})
+
+	Context("when the direct route is not supported", func() {
+		It("should plan multiple steps", func() {
+			registry := registry
+			registry.Routes = []tx.Route{
+				{From: multichain.Solana, To: multichain.Bitcoin},
+				{From: multichain.Bitcoin, To: multichain.Polygon},
+			}
+			graph := tx.NewRouteGraph(registry)
+			plan, err := graph.PlanRoute(multichain.BTC, multichain.Solana, multichain.Polygon, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.Steps).To(Equal([]tx.RouteStep{
+				{Selector: "BTC/fromSolana", Source: multichain.Solana, Destination: multichain.Bitcoin},
+				{Selector: "BTC/toPolygon", Source: multichain.Bitcoin, Destination: multichain.Polygon},
+			}))
+			Expect(plan.Cost).To(Equal(2.0))
+			Expect(plan.Selectors()).To(Equal([]tx.Selector{"BTC/fromSolana", "BTC/toPolygon"}))
+		})
+	})
+
+	Context("when the direct route is disabled", func() {
+		It("should plan around it", func() {
+			graph := tx.NewRouteGraph(registry)
+			Expect(graph.Disable("BTC/toPolygonFromSolana")).To(Succeed())
+			plans, err := graph.PlanRoutes(multichain.BTC, multichain.Solana, multichain.Polygon, nil, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plans[0].Selectors()).To(Equal([]tx.Selector{"BTC/fromSolana", "BTC/toPolygon"}))
+			Expect(plans[1].Selectors()).To(Equal([]tx.Selector{"BTC/toEthereumFromSolana", "BTC/toPolygonFromEthereum"}))
+			for _, plan := range plans {
+				for i, step := range plan.Steps {
+					if i == 0 {
+						Expect(step.Source).To(Equal(multichain.Solana))
+					} else {
+						Expect(step.Source).To(Equal(plan.Steps[i-1].Destination))
+					}
+				}
+				Expect(plan.Steps[len(plan.Steps)-1].Destination).To(Equal(multichain.Polygon))
+			}
+		})
+	})
+
+	Context("when using a cost function", func() {
+		It("should rank plans by their cost", func() {
+			graph := tx.NewRouteGraph(registry)
+			// Make releasing to Bitcoin very cheap, and everything else
+			// expensive.
+			cost := func(asset multichain.Asset, step tx.RouteStep) float64 {
+				Expect(asset).To(Equal(multichain.BTC))
+				if step.Destination == multichain.Bitcoin || step.Source == multichain.Bitcoin {
+					return 1
+				}
+				return 10
+			}
+			plans, err := graph.PlanRoutes(multichain.BTC, multichain.Solana, multichain.Polygon, cost, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plans[0].Selectors()).To(Equal([]tx.Selector{"BTC/fromSolana", "BTC/toPolygon"}))
+			Expect(plans[0].Cost).To(Equal(2.0))
+			Expect(plans[1].Selectors()).To(Equal([]tx.Selector{"BTC/toPolygonFromSolana"}))
+			for i := 1; i < len(plans); i++ {
+				Expect(plans[i].Cost).To(BeNumerically(">=", plans[i-1].Cost))
+			}
+		})
+	})
+
+	Context("when there are many routes", func() {
+		It("should return the cheapest plans, in the same order as ranking every route", func() {
+			graph := tx.NewRouteGraph(txutil.DefaultNetworkRegistry())
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			costs := map[tx.Selector]float64{}
+			cost := func(asset multichain.Asset, step tx.RouteStep) float64 {
+				if _, ok := costs[step.Selector]; !ok {
+					costs[step.Selector] = float64(r.Intn(5))
+				}
+				return costs[step.Selector]
+			}
+
+			plans, err := graph.PlanRoutes(multichain.BTC, multichain.Ethereum, multichain.Solana, cost, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plans).To(HaveLen(10))
+
+			routes := graph.Routes(multichain.BTC, multichain.Ethereum, multichain.Solana, tx.MaxRouteHops)
+			expected := make([]tx.RoutePlan, len(routes))
+			for i, route := range routes {
+				expected[i] = tx.RoutePlan{Asset: multichain.BTC}
+				for _, selector := range route {
+					step := tx.RouteStep{Selector: selector, Source: selector.Source(), Destination: selector.Destination()}
+					expected[i].Steps = append(expected[i].Steps, step)
+					expected[i].Cost += cost(multichain.BTC, step)
+				}
+			}
+			sort.SliceStable(expected, func(i, j int) bool {
+				if expected[i].Cost != expected[j].Cost {
+					return expected[i].Cost < expected[j].Cost
+				}
+				return len(expected[i].Steps) < len(expected[j].Steps)
+			})
+			Expect(plans).To(Equal(expected[:10]))
+
+			all, err := graph.PlanRoutes(multichain.BTC, multichain.Ethereum, multichain.Solana, cost, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(all).To(Equal(expected))
+		})
+	})
+
+	Context("when a cost is negative", func() {
+		It("should return an error", func() {
+			graph := tx.NewRouteGraph(registry)
+			cost := func(multichain.Asset, tx.RouteStep) float64 { return -1 }
+			_, err := graph.PlanRoute(multichain.BTC, multichain.Solana, multichain.Polygon, cost)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when there is no route", func() {
+		It("should return an error", func() {
+			graph := tx.NewRouteGraph(registry)
+			_, err := graph.PlanRoute(multichain.BTC, multichain.Solana, multichain.Goerli, nil)
+			Expect(err).To(HaveOccurred())
+			_, err = graph.PlanRoutes(multichain.ZEC, multichain.Solana, multichain.Polygon, tx.HopCost, 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
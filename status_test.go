@@ -22,6 +22,7 @@ var _ = Describe("Transaction status", func() {
 			for trial := 0; trial < numTrials; trial++ {
 				Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
 				Expect(func() { packutil.JSONFuzz(t) }).ToNot(Panic())
+				Expect(func() { CBORFuzz(t) }).ToNot(Panic())
 			}
 		})
 	})
@@ -31,6 +32,7 @@ var _ = Describe("Transaction status", func() {
 			for trial := 0; trial < numTrials; trial++ {
 				Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
 				Expect(JSONMarshalUnmarshalCheck(t)).To(Succeed())
+				Expect(CBORMarshalUnmarshalCheck(t)).To(Succeed())
 			}
 		})
 	})
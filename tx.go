@@ -9,6 +9,7 @@
 package tx
 
 import (
+	"fmt"
 	"math/rand"
 	"reflect"
 	"testing/quick"
@@ -82,6 +83,20 @@ func NewTx(selector Selector, input pack.Typed) (Tx, error) {
 	return Tx{Version: Version1, Hash: hash, Selector: selector, Input: input}, nil
 }
 
+// VerifyHash returns an error wrapping ErrInvalidHash if the hash of the
+// transaction is not the hash of its version, selector, and input. Callers
+// that verify the same transaction many times can use a HashMemo instead.
+func (tx Tx) VerifyHash() error {
+	hash, err := NewTxHash(tx.Version, tx.Selector, tx.Input)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if hash != tx.Hash {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidHash, hash, tx.Hash)
+	}
+	return nil
+}
+
 // Generate allows us to quickly generate random transactions. This is mostly
 // used for writing tests.
 func (tx Tx) Generate(r *rand.Rand, size int) reflect.Value {
@@ -1,6 +1,7 @@
 package tx_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -58,3 +59,61 @@ func JSONMarshalUnmarshalCheck(t reflect.Type) error {
 	}
 	return nil
 }
+
+// CBORFuzz is the same as the Fuzz testing function exposed by surge, but it
+// uses CBOR.
+func CBORFuzz(t reflect.Type) {
+	// Fuzz data
+	data, ok := quick.Value(reflect.TypeOf([]byte{}), rand.New(rand.NewSource(time.Now().UnixNano())))
+	if !ok {
+		panic(fmt.Errorf("cannot generate value of type %v", t))
+	}
+	// Unmarshal
+	x := reflect.New(t)
+	if err := x.Interface().(cborUnmarshaler).UnmarshalCBOR(data.Bytes()); err != nil {
+		// Ignore the error, because we are only interested in whether or not
+		// the unmarshaling causes a panic.
+	}
+}
+
+// CBORMarshalUnmarshalCheck is the same as the MarshalUnmarshalCheck testing
+// function exposed by surge, but it uses CBOR. It also checks that the
+// encoding is deterministic.
+func CBORMarshalUnmarshalCheck(t reflect.Type) error {
+	// Generate
+	x, ok := quick.Value(t, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if !ok {
+		return fmt.Errorf("cannot generate value of type %v", t)
+	}
+	// Marshal
+	data, err := x.Interface().(cborMarshaler).MarshalCBOR()
+	if err != nil {
+		return fmt.Errorf("cannot marshal: %v", err)
+	}
+	// Unmarshal
+	y := reflect.New(t)
+	if err := y.Interface().(cborUnmarshaler).UnmarshalCBOR(data); err != nil {
+		return fmt.Errorf("cannot unmarshal: %v", err)
+	}
+	// Equality
+	if !reflect.DeepEqual(x.Interface(), y.Elem().Interface()) {
+		return fmt.Errorf("unequal")
+	}
+	// Determinism
+	dataAgain, err := y.Elem().Interface().(cborMarshaler).MarshalCBOR()
+	if err != nil {
+		return fmt.Errorf("cannot marshal: %v", err)
+	}
+	if !bytes.Equal(data, dataAgain) {
+		return fmt.Errorf("non-deterministic")
+	}
+	return nil
+}
+
+type cborMarshaler interface {
+	MarshalCBOR() ([]byte, error)
+}
+
+type cborUnmarshaler interface {
+	UnmarshalCBOR([]byte) error
+}
@@ -23,6 +23,7 @@ var _ = Describe("Transactions", func() {
 		It("should not panic", func() {
 			Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
 			Expect(func() { JSONFuzz(t) }).ToNot(Panic())
+			Expect(func() { CBORFuzz(t) }).ToNot(Panic())
 		})
 	})
 
@@ -31,6 +32,7 @@ var _ = Describe("Transactions", func() {
 			for trial := 0; trial < numTrials; trial++ {
 				Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
 				Expect(JSONMarshalUnmarshalCheck(t)).To(Succeed())
+				Expect(CBORMarshalUnmarshalCheck(t)).To(Succeed())
 			}
 		})
 	})
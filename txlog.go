@@ -0,0 +1,263 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/renproject/surge"
+)
+
+// TxLogVersion is the version of the transaction log format that is written
+// by the TxWriter.
+const TxLogVersion = uint16(1)
+
+// A transaction log is a stream of transactions with statuses. It begins with
+// a header:
+//
+//	magic:   8 bytes ("RENTXLOG")
+//	version: uint16 (big-endian)
+//
+// and is followed by any number of records:
+//
+//	sync:     4 bytes (0xE2 0x9B 0x93 0x0A)
+//	length:   uint32 (big-endian) length of the payload
+//	checksum: uint32 (big-endian) CRC-32C of the length and payload
+//	payload:  surge encoded WithStatus
+//
+// The sync marker allows a reader to find the start of the next record after
+// a corrupt record.
+var (
+	txLogMagic = []byte("RENTXLOG")
+	txLogSync  = []byte{0xE2, 0x9B, 0x93, 0x0A}
+
+	txLogHeaderLen       = len(txLogMagic) + 2
+	txLogRecordHeaderLen = len(txLogSync) + 4 + 4
+
+	txLogCRCTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+var (
+	// ErrTxLogHeader is returned when a transaction log does not begin with a
+	// valid header.
+	ErrTxLogHeader = errors.New("bad tx log header")
+
+	// ErrTxLogCorrupt is returned when a record in a transaction log is
+	// corrupt. The record is skipped, and reading can continue.
+	ErrTxLogCorrupt = errors.New("corrupt tx log record")
+
+	// ErrTxLogTruncated is returned when a transaction log ends partway
+	// through a record. Reading can continue, because it is possible that the
+	// length of the record was corrupt, and other records follow.
+	ErrTxLogTruncated = errors.New("truncated tx log")
+)
+
+// A TxWriter writes transactions with statuses to a transaction log.
+type TxWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewTxWriter returns a TxWriter that writes to the given writer. The header
+// of the transaction log is written immediately.
+func NewTxWriter(w io.Writer) (*TxWriter, error) {
+	header := make([]byte, txLogHeaderLen)
+	copy(header, txLogMagic)
+	binary.BigEndian.PutUint16(header[len(txLogMagic):], TxLogVersion)
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("writing header: %v", err)
+	}
+	return &TxWriter{w: w}, nil
+}
+
+// Write a transaction with its status to the log as a single record. The
+// underlying writer receives exactly one write per record.
+func (w *TxWriter) Write(tx WithStatus) error {
+	n := txLogRecordHeaderLen + tx.SizeHint()
+	if cap(w.buf) < n {
+		w.buf = make([]byte, n)
+	}
+	buf := w.buf[:n]
+	if _, _, err := tx.Marshal(buf[txLogRecordHeaderLen:], surge.MaxBytes); err != nil {
+		return fmt.Errorf("marshaling tx: %v", err)
+	}
+	copy(buf, txLogSync)
+	binary.BigEndian.PutUint32(buf[len(txLogSync):], uint32(n-txLogRecordHeaderLen))
+	binary.BigEndian.PutUint32(buf[len(txLogSync)+4:], txLogChecksum(buf))
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("writing record: %v", err)
+	}
+	return nil
+}
+
+// A TxReader reads transactions with statuses from a transaction log.
+type TxReader struct {
+	r       io.Reader
+	version uint16
+
+	buf    []byte // Unconsumed data from the underlying reader.
+	eof    bool   // Whether or not the underlying reader is exhausted.
+	offset int64  // Offset of the first unconsumed byte in the log.
+	resync bool   // Whether or not the reader is looking for a sync marker.
+}
+
+// NewTxReader returns a TxReader that reads from the given reader. The header
+// of the transaction log is read immediately, and an error is returned if it
+// is not valid.
+func NewTxReader(r io.Reader) (*TxReader, error) {
+	reader := &TxReader{r: r}
+	if err := reader.fill(txLogHeaderLen); err != nil {
+		return nil, err
+	}
+	if len(reader.buf) < txLogHeaderLen || !bytes.Equal(reader.buf[:len(txLogMagic)], txLogMagic) {
+		return nil, ErrTxLogHeader
+	}
+	reader.version = binary.BigEndian.Uint16(reader.buf[len(txLogMagic):])
+	if reader.version != TxLogVersion {
+		return nil, fmt.Errorf("%w: unsupported version %v", ErrTxLogHeader, reader.version)
+	}
+	reader.consume(txLogHeaderLen)
+	return reader, nil
+}
+
+// Version of the transaction log format.
+func (r *TxReader) Version() uint16 {
+	return r.version
+}
+
+// Offset returns the number of bytes of the log that have been consumed.
+func (r *TxReader) Offset() int64 {
+	return r.offset
+}
+
+// Read the next transaction with its status from the log. It returns io.EOF
+// when there are no more records. It returns an error wrapping
+// ErrTxLogCorrupt or ErrTxLogTruncated when a record cannot be read. In both
+// cases, the record is skipped and the next call will resume from the next
+// valid record. All other errors come from the underlying reader.
+func (r *TxReader) Read() (WithStatus, error) {
+	if r.resync {
+		if err := r.sync(); err != nil {
+			return WithStatus{}, err
+		}
+	}
+
+	// Read the record header.
+	if err := r.fill(txLogRecordHeaderLen); err != nil {
+		return WithStatus{}, err
+	}
+	if len(r.buf) == 0 {
+		return WithStatus{}, io.EOF
+	}
+	if len(r.buf) < txLogRecordHeaderLen {
+		return WithStatus{}, r.skip(ErrTxLogTruncated, "incomplete record header")
+	}
+	if !bytes.Equal(r.buf[:len(txLogSync)], txLogSync) {
+		return WithStatus{}, r.skip(ErrTxLogCorrupt, "missing sync marker")
+	}
+	n := binary.BigEndian.Uint32(r.buf[len(txLogSync):])
+	if n > uint32(surge.MaxBytes) {
+		return WithStatus{}, r.skip(ErrTxLogCorrupt, fmt.Sprintf("record length %v is too large", n))
+	}
+
+	// Read the record payload.
+	if err := r.fill(txLogRecordHeaderLen + int(n)); err != nil {
+		return WithStatus{}, err
+	}
+	if len(r.buf) < txLogRecordHeaderLen+int(n) {
+		return WithStatus{}, r.skip(ErrTxLogTruncated, "incomplete record payload")
+	}
+	record := r.buf[:txLogRecordHeaderLen+int(n)]
+	if binary.BigEndian.Uint32(record[len(txLogSync)+4:]) != txLogChecksum(record) {
+		return WithStatus{}, r.skip(ErrTxLogCorrupt, "checksum mismatch")
+	}
+	tx := WithStatus{}
+	if err := surge.FromBinary(&tx, record[txLogRecordHeaderLen:]); err != nil {
+		return WithStatus{}, r.skip(ErrTxLogCorrupt, fmt.Sprintf("unmarshaling tx: %v", err))
+	}
+	r.consume(len(record))
+	return tx, nil
+}
+
+// skip the first byte of the current record, and enter resync mode so that the
+// next read will search for the next sync marker. It returns an error that
+// describes why the record was skipped.
+func (r *TxReader) skip(err error, reason string) error {
+	offset := r.offset
+	r.consume(1)
+	r.resync = true
+	return fmt.Errorf("%w at offset %v: %v", err, offset, reason)
+}
+
+// sync consumes data until the start of the next sync marker, or until there is
+// no more data.
+func (r *TxReader) sync() error {
+	for {
+		if i := bytes.Index(r.buf, txLogSync); i >= 0 {
+			r.consume(i)
+			r.resync = false
+			return nil
+		}
+		if r.eof {
+			r.consume(len(r.buf))
+			r.resync = false
+			return nil
+		}
+		// Keep enough data to find a sync marker that is split across reads.
+		if len(r.buf) >= len(txLogSync) {
+			r.consume(len(r.buf) - len(txLogSync) + 1)
+		}
+		if err := r.fill(len(r.buf) + 1); err != nil {
+			return err
+		}
+	}
+}
+
+// fill the buffer until it holds at least n bytes, or the underlying reader is
+// exhausted.
+func (r *TxReader) fill(n int) error {
+	if len(r.buf) >= n || r.eof {
+		return nil
+	}
+	if cap(r.buf) < n {
+		// Move unconsumed data to the front of a larger buffer. Consumed data
+		// is dropped, so the buffer does not grow without bound.
+		size := 2 * cap(r.buf)
+		if size < n {
+			size = n
+		}
+		if size < 4096 {
+			size = 4096
+		}
+		buf := make([]byte, len(r.buf), size)
+		copy(buf, r.buf)
+		r.buf = buf
+	}
+	for len(r.buf) < n {
+		m, err := r.r.Read(r.buf[len(r.buf):cap(r.buf)])
+		r.buf = r.buf[:len(r.buf)+m]
+		if err == io.EOF {
+			r.eof = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TxReader) consume(n int) {
+	r.buf = r.buf[n:]
+	r.offset += int64(n)
+}
+
+// txLogChecksum returns the checksum of a record. It covers the length and the
+// payload of the record.
+func txLogChecksum(record []byte) uint32 {
+	crc := crc32.Checksum(record[len(txLogSync):len(txLogSync)+4], txLogCRCTable)
+	return crc32.Update(crc, txLogCRCTable, record[txLogRecordHeaderLen:])
+}
@@ -0,0 +1,165 @@
+package tx_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing/iotest"
+
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transaction log", func() {
+
+	writeLog := func(txs []tx.WithStatus) ([]byte, []int) {
+		buf := new(bytes.Buffer)
+		w, err := tx.NewTxWriter(buf)
+		Expect(err).ToNot(HaveOccurred())
+		offsets := make([]int, len(txs))
+		for i, transaction := range txs {
+			offsets[i] = buf.Len()
+			Expect(w.Write(transaction)).To(Succeed())
+		}
+		return buf.Bytes(), offsets
+	}
+
+	expectEqual := func(got, expected tx.WithStatus) {
+		gotData, err := surge.ToBinary(got)
+		Expect(err).ToNot(HaveOccurred())
+		expectedData, err := surge.ToBinary(expected)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gotData).To(Equal(expectedData))
+	}
+
+	Context("when writing and then reading transactions", func() {
+		It("should return the same transactions in the same order", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxsWithStatus(r, 100)
+			data, _ := writeLog(txs)
+
+			// Read one byte at a time to make sure that records are buffered
+			// correctly.
+			reader, err := tx.NewTxReader(iotest.OneByteReader(bytes.NewReader(data)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reader.Version()).To(Equal(tx.TxLogVersion))
+			for _, expected := range txs {
+				got, err := reader.Read()
+				Expect(err).ToNot(HaveOccurred())
+				expectEqual(got, expected)
+			}
+			_, err = reader.Read()
+			Expect(err).To(Equal(io.EOF))
+			Expect(reader.Offset()).To(Equal(int64(len(data))))
+		})
+	})
+
+	Context("when the log is empty", func() {
+		It("should return io.EOF", func() {
+			data, _ := writeLog(nil)
+			reader, err := tx.NewTxReader(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reader.Read()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+
+	Context("when the header is bad", func() {
+		It("should return an error", func() {
+			data, _ := writeLog(nil)
+
+			_, err := tx.NewTxReader(bytes.NewReader(data[:len(data)-1]))
+			Expect(errors.Is(err, tx.ErrTxLogHeader)).To(BeTrue())
+
+			badMagic := append([]byte{}, data...)
+			badMagic[0] ^= 0xFF
+			_, err = tx.NewTxReader(bytes.NewReader(badMagic))
+			Expect(errors.Is(err, tx.ErrTxLogHeader)).To(BeTrue())
+
+			badVersion := append([]byte{}, data...)
+			badVersion[len(badVersion)-1]++
+			_, err = tx.NewTxReader(bytes.NewReader(badVersion))
+			Expect(errors.Is(err, tx.ErrTxLogHeader)).To(BeTrue())
+		})
+	})
+
+	Context("when the log is truncated", func() {
+		It("should return the complete records and then an error", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxsWithStatus(r, 10)
+			data, offsets := writeLog(txs)
+			data = data[:offsets[9]+(len(data)-offsets[9])/2]
+
+			reader, err := tx.NewTxReader(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			for _, expected := range txs[:9] {
+				got, err := reader.Read()
+				Expect(err).ToNot(HaveOccurred())
+				expectEqual(got, expected)
+			}
+			_, err = reader.Read()
+			Expect(errors.Is(err, tx.ErrTxLogTruncated)).To(BeTrue())
+			_, err = reader.Read()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+
+	Context("when a record is corrupt", func() {
+		It("should skip the record and resume from the next record", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxsWithStatus(r, 10)
+			for _, corrupt := range []int{0, 4, 9} {
+				data, offsets := writeLog(txs)
+				end := len(data)
+				if corrupt < 9 {
+					end = offsets[corrupt+1]
+				}
+				// Corrupt a byte in the payload, which is after the 12 byte
+				// record header.
+				pos := offsets[corrupt] + 12 + r.Intn(end-offsets[corrupt]-12)
+				data[pos] ^= 0xFF
+
+				reader, err := tx.NewTxReader(bytes.NewReader(data))
+				Expect(err).ToNot(HaveOccurred())
+				for i, expected := range txs {
+					got, err := reader.Read()
+					if i == corrupt {
+						Expect(errors.Is(err, tx.ErrTxLogCorrupt)).To(BeTrue())
+						continue
+					}
+					Expect(err).ToNot(HaveOccurred())
+					expectEqual(got, expected)
+				}
+				_, err = reader.Read()
+				Expect(err).To(Equal(io.EOF))
+			}
+		})
+	})
+
+	Context("when the length of a record is corrupt", func() {
+		It("should skip the record and resume from the next record", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			txs := txutil.RandomGoodTxsWithStatus(r, 3)
+			data, offsets := writeLog(txs)
+			// Make the length of the first record larger than the log.
+			data[offsets[0]+4] = 0x01
+
+			reader, err := tx.NewTxReader(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reader.Read()
+			Expect(errors.Is(err, tx.ErrTxLogTruncated)).To(BeTrue())
+			for _, expected := range txs[1:] {
+				got, err := reader.Read()
+				Expect(err).ToNot(HaveOccurred())
+				expectEqual(got, expected)
+			}
+			_, err = reader.Read()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+})
@@ -0,0 +1,1357 @@
+// Protocol buffer definitions for RenVM transactions. These mirror the types in
+// the github.com/renproject/tx package, and can be converted to and from them
+// without loss. Services that cannot use surge (the binary encoding used by
+// RenVM) should use these definitions instead.
+//
+// The Go code in this package is generated from this file, using protoc 3.17.3
+// and the version of protoc-gen-go in go.mod, by running:
+//
+//  go generate ./txpb
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.17.3
+// source: tx.proto
+
+package txpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Status of a transaction. The values are identical to those used by the
+// native status type.
+type Status int32
+
+const (
+	Status_STATUS_NIL        Status = 0
+	Status_STATUS_CONFIRMING Status = 1
+	Status_STATUS_PENDING    Status = 2
+	Status_STATUS_EXECUTING  Status = 3
+	Status_STATUS_DONE       Status = 4
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_NIL",
+		1: "STATUS_CONFIRMING",
+		2: "STATUS_PENDING",
+		3: "STATUS_EXECUTING",
+		4: "STATUS_DONE",
+	}
+	Status_value = map[string]int32{
+		"STATUS_NIL":        0,
+		"STATUS_CONFIRMING": 1,
+		"STATUS_PENDING":    2,
+		"STATUS_EXECUTING":  3,
+		"STATUS_DONE":       4,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_tx_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_tx_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{0}
+}
+
+// Kind is the abstract "type of a type". The values are identical to those
+// used by the native kind type.
+type Kind int32
+
+const (
+	Kind_KIND_NIL     Kind = 0
+	Kind_KIND_BOOL    Kind = 1
+	Kind_KIND_U8      Kind = 2
+	Kind_KIND_U16     Kind = 3
+	Kind_KIND_U32     Kind = 4
+	Kind_KIND_U64     Kind = 5
+	Kind_KIND_U128    Kind = 6
+	Kind_KIND_U256    Kind = 7
+	Kind_KIND_STRING  Kind = 10
+	Kind_KIND_BYTES   Kind = 11
+	Kind_KIND_BYTES32 Kind = 12
+	Kind_KIND_BYTES65 Kind = 13
+	Kind_KIND_STRUCT  Kind = 20
+	Kind_KIND_LIST    Kind = 21
+)
+
+// Enum value maps for Kind.
+var (
+	Kind_name = map[int32]string{
+		0:  "KIND_NIL",
+		1:  "KIND_BOOL",
+		2:  "KIND_U8",
+		3:  "KIND_U16",
+		4:  "KIND_U32",
+		5:  "KIND_U64",
+		6:  "KIND_U128",
+		7:  "KIND_U256",
+		10: "KIND_STRING",
+		11: "KIND_BYTES",
+		12: "KIND_BYTES32",
+		13: "KIND_BYTES65",
+		20: "KIND_STRUCT",
+		21: "KIND_LIST",
+	}
+	Kind_value = map[string]int32{
+		"KIND_NIL":     0,
+		"KIND_BOOL":    1,
+		"KIND_U8":      2,
+		"KIND_U16":     3,
+		"KIND_U32":     4,
+		"KIND_U64":     5,
+		"KIND_U128":    6,
+		"KIND_U256":    7,
+		"KIND_STRING":  10,
+		"KIND_BYTES":   11,
+		"KIND_BYTES32": 12,
+		"KIND_BYTES65": 13,
+		"KIND_STRUCT":  20,
+		"KIND_LIST":    21,
+	}
+)
+
+func (x Kind) Enum() *Kind {
+	p := new(Kind)
+	*p = x
+	return p
+}
+
+func (x Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_tx_proto_enumTypes[1].Descriptor()
+}
+
+func (Kind) Type() protoreflect.EnumType {
+	return &file_tx_proto_enumTypes[1]
+}
+
+func (x Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Kind.Descriptor instead.
+func (Kind) EnumDescriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{1}
+}
+
+// Tx represents a RenVM cross-chain transaction.
+type Tx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Hash of the transaction that uniquely identifies it. It is always 32
+	// bytes.
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Version of the transaction.
+	Version *Version `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Selector of the gateway function that is being called by this
+	// transaction.
+	Selector *Selector `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Input values are provided by an external user when the transaction is
+	// submitted.
+	Input *Typed `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`
+	// Output values are generated as part of execution.
+	Output *Typed `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *Tx) Reset() {
+	*x = Tx{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tx) ProtoMessage() {}
+
+func (x *Tx) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tx.ProtoReflect.Descriptor instead.
+func (*Tx) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Tx) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *Tx) GetVersion() *Version {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Tx) GetSelector() *Selector {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *Tx) GetInput() *Typed {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *Tx) GetOutput() *Typed {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+// WithStatus is a combination of a transaction and its current status.
+type WithStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tx     *Tx    `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=renproject.tx.Status" json:"status,omitempty"`
+}
+
+func (x *WithStatus) Reset() {
+	*x = WithStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WithStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithStatus) ProtoMessage() {}
+
+func (x *WithStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithStatus.ProtoReflect.Descriptor instead.
+func (*WithStatus) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WithStatus) GetTx() *Tx {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+func (x *WithStatus) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_NIL
+}
+
+// Version of a transaction. For example, "1".
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Version) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Selector identifies a specific function from a specific contract. For
+// example, "BTC/toEthereum".
+type Selector struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Selector) Reset() {
+	*x = Selector{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Selector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Selector) ProtoMessage() {}
+
+func (x *Selector) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Selector.ProtoReflect.Descriptor instead.
+func (*Selector) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Selector) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Typed is a well-typed struct. The order of the fields is significant, and
+// must be preserved, because it affects the transaction hash.
+type Typed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields []*Field `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *Typed) Reset() {
+	*x = Typed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Typed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Typed) ProtoMessage() {}
+
+func (x *Typed) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Typed.ProtoReflect.Descriptor instead.
+func (*Typed) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Typed) GetFields() []*Field {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// Field is a named value within a struct.
+type Field struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value *Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Field) Reset() {
+	*x = Field{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Field) ProtoMessage() {}
+
+func (x *Field) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Field.ProtoReflect.Descriptor instead.
+func (*Field) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Field) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Field) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Value is a single value of any kind. Integers wider than 64 bits are
+// represented as big-endian bytes that are exactly 16 (for u128) or 32 (for
+// u256) bytes long.
+type Value struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Value:
+	//	*Value_Bool
+	//	*Value_U8
+	//	*Value_U16
+	//	*Value_U32
+	//	*Value_U64
+	//	*Value_U128
+	//	*Value_U256
+	//	*Value_String_
+	//	*Value_Bytes
+	//	*Value_Bytes32
+	//	*Value_Bytes65
+	//	*Value_Struct
+	//	*Value_List
+	Value isValue_Value `protobuf_oneof:"value"`
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *Value) GetValue() isValue_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *Value) GetBool() bool {
+	if x, ok := x.GetValue().(*Value_Bool); ok {
+		return x.Bool
+	}
+	return false
+}
+
+func (x *Value) GetU8() uint32 {
+	if x, ok := x.GetValue().(*Value_U8); ok {
+		return x.U8
+	}
+	return 0
+}
+
+func (x *Value) GetU16() uint32 {
+	if x, ok := x.GetValue().(*Value_U16); ok {
+		return x.U16
+	}
+	return 0
+}
+
+func (x *Value) GetU32() uint32 {
+	if x, ok := x.GetValue().(*Value_U32); ok {
+		return x.U32
+	}
+	return 0
+}
+
+func (x *Value) GetU64() uint64 {
+	if x, ok := x.GetValue().(*Value_U64); ok {
+		return x.U64
+	}
+	return 0
+}
+
+func (x *Value) GetU128() []byte {
+	if x, ok := x.GetValue().(*Value_U128); ok {
+		return x.U128
+	}
+	return nil
+}
+
+func (x *Value) GetU256() []byte {
+	if x, ok := x.GetValue().(*Value_U256); ok {
+		return x.U256
+	}
+	return nil
+}
+
+func (x *Value) GetString_() string {
+	if x, ok := x.GetValue().(*Value_String_); ok {
+		return x.String_
+	}
+	return ""
+}
+
+func (x *Value) GetBytes() []byte {
+	if x, ok := x.GetValue().(*Value_Bytes); ok {
+		return x.Bytes
+	}
+	return nil
+}
+
+func (x *Value) GetBytes32() []byte {
+	if x, ok := x.GetValue().(*Value_Bytes32); ok {
+		return x.Bytes32
+	}
+	return nil
+}
+
+func (x *Value) GetBytes65() []byte {
+	if x, ok := x.GetValue().(*Value_Bytes65); ok {
+		return x.Bytes65
+	}
+	return nil
+}
+
+func (x *Value) GetStruct() *Typed {
+	if x, ok := x.GetValue().(*Value_Struct); ok {
+		return x.Struct
+	}
+	return nil
+}
+
+func (x *Value) GetList() *List {
+	if x, ok := x.GetValue().(*Value_List); ok {
+		return x.List
+	}
+	return nil
+}
+
+type isValue_Value interface {
+	isValue_Value()
+}
+
+type Value_Bool struct {
+	Bool bool `protobuf:"varint,1,opt,name=bool,proto3,oneof"`
+}
+
+type Value_U8 struct {
+	U8 uint32 `protobuf:"varint,2,opt,name=u8,proto3,oneof"`
+}
+
+type Value_U16 struct {
+	U16 uint32 `protobuf:"varint,3,opt,name=u16,proto3,oneof"`
+}
+
+type Value_U32 struct {
+	U32 uint32 `protobuf:"varint,4,opt,name=u32,proto3,oneof"`
+}
+
+type Value_U64 struct {
+	U64 uint64 `protobuf:"varint,5,opt,name=u64,proto3,oneof"`
+}
+
+type Value_U128 struct {
+	U128 []byte `protobuf:"bytes,6,opt,name=u128,proto3,oneof"`
+}
+
+type Value_U256 struct {
+	U256 []byte `protobuf:"bytes,7,opt,name=u256,proto3,oneof"`
+}
+
+type Value_String_ struct {
+	String_ string `protobuf:"bytes,10,opt,name=string,proto3,oneof"`
+}
+
+type Value_Bytes struct {
+	Bytes []byte `protobuf:"bytes,11,opt,name=bytes,proto3,oneof"`
+}
+
+type Value_Bytes32 struct {
+	Bytes32 []byte `protobuf:"bytes,12,opt,name=bytes32,proto3,oneof"`
+}
+
+type Value_Bytes65 struct {
+	Bytes65 []byte `protobuf:"bytes,13,opt,name=bytes65,proto3,oneof"`
+}
+
+type Value_Struct struct {
+	Struct *Typed `protobuf:"bytes,20,opt,name=struct,proto3,oneof"`
+}
+
+type Value_List struct {
+	List *List `protobuf:"bytes,21,opt,name=list,proto3,oneof"`
+}
+
+func (*Value_Bool) isValue_Value() {}
+
+func (*Value_U8) isValue_Value() {}
+
+func (*Value_U16) isValue_Value() {}
+
+func (*Value_U32) isValue_Value() {}
+
+func (*Value_U64) isValue_Value() {}
+
+func (*Value_U128) isValue_Value() {}
+
+func (*Value_U256) isValue_Value() {}
+
+func (*Value_String_) isValue_Value() {}
+
+func (*Value_Bytes) isValue_Value() {}
+
+func (*Value_Bytes32) isValue_Value() {}
+
+func (*Value_Bytes65) isValue_Value() {}
+
+func (*Value_Struct) isValue_Value() {}
+
+func (*Value_List) isValue_Value() {}
+
+// List is a list of values that all have the same type. The element type is
+// always given, so that empty lists can be represented without loss.
+type List struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  *Type    `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Elems []*Value `protobuf:"bytes,2,rep,name=elems,proto3" json:"elems,omitempty"`
+}
+
+func (x *List) Reset() {
+	*x = List{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *List) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*List) ProtoMessage() {}
+
+func (x *List) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use List.ProtoReflect.Descriptor instead.
+func (*List) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *List) GetType() *Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *List) GetElems() []*Value {
+	if x != nil {
+		return x.Elems
+	}
+	return nil
+}
+
+// Type is a concrete type definition for a value.
+type Type struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Type:
+	//	*Type_Kind
+	//	*Type_Struct
+	//	*Type_List
+	Type isType_Type `protobuf_oneof:"type"`
+}
+
+func (x *Type) Reset() {
+	*x = Type{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Type) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Type) ProtoMessage() {}
+
+func (x *Type) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Type.ProtoReflect.Descriptor instead.
+func (*Type) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{8}
+}
+
+func (m *Type) GetType() isType_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (x *Type) GetKind() Kind {
+	if x, ok := x.GetType().(*Type_Kind); ok {
+		return x.Kind
+	}
+	return Kind_KIND_NIL
+}
+
+func (x *Type) GetStruct() *StructType {
+	if x, ok := x.GetType().(*Type_Struct); ok {
+		return x.Struct
+	}
+	return nil
+}
+
+func (x *Type) GetList() *Type {
+	if x, ok := x.GetType().(*Type_List); ok {
+		return x.List
+	}
+	return nil
+}
+
+type isType_Type interface {
+	isType_Type()
+}
+
+type Type_Kind struct {
+	// Kind is used for all types that are not structs or lists.
+	Kind Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=renproject.tx.Kind,oneof"`
+}
+
+type Type_Struct struct {
+	Struct *StructType `protobuf:"bytes,20,opt,name=struct,proto3,oneof"`
+}
+
+type Type_List struct {
+	// List is the type of the elements in the list.
+	List *Type `protobuf:"bytes,21,opt,name=list,proto3,oneof"`
+}
+
+func (*Type_Kind) isType_Type() {}
+
+func (*Type_Struct) isType_Type() {}
+
+func (*Type_List) isType_Type() {}
+
+// StructType defines the names and types of the fields in a struct.
+type StructType struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields []*StructTypeField `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *StructType) Reset() {
+	*x = StructType{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StructType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructType) ProtoMessage() {}
+
+func (x *StructType) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructType.ProtoReflect.Descriptor instead.
+func (*StructType) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StructType) GetFields() []*StructTypeField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// StructTypeField is the name and type of a field within a struct.
+type StructTypeField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type *Type  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *StructTypeField) Reset() {
+	*x = StructTypeField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StructTypeField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructTypeField) ProtoMessage() {}
+
+func (x *StructTypeField) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructTypeField.ProtoReflect.Descriptor instead.
+func (*StructTypeField) Descriptor() ([]byte, []int) {
+	return file_tx_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StructTypeField) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StructTypeField) GetType() *Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+var File_tx_proto protoreflect.FileDescriptor
+
+var file_tx_proto_rawDesc = []byte{
+	0x0a, 0x08, 0x74, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x72, 0x65, 0x6e, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x22, 0xd9, 0x01, 0x0a, 0x02, 0x54, 0x78,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x30, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x05, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x6e,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64,
+	0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64, 0x52, 0x06, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5e, 0x0a, 0x0a, 0x57, 0x69, 0x74, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e,
+	0x54, 0x78, 0x52, 0x02, 0x74, 0x78, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x1f, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x20, 0x0a, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x35, 0x0a, 0x05, 0x54, 0x79, 0x70, 0x65,
+	0x64, 0x12, 0x2c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74,
+	0x78, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22,
+	0x47, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x65,
+	0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xe5, 0x02, 0x0a, 0x05, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x00, 0x52, 0x04, 0x62, 0x6f, 0x6f, 0x6c, 0x12, 0x10, 0x0a, 0x02, 0x75, 0x38, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x02, 0x75, 0x38, 0x12, 0x12, 0x0a, 0x03, 0x75, 0x31,
+	0x36, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x03, 0x75, 0x31, 0x36, 0x12, 0x12,
+	0x0a, 0x03, 0x75, 0x33, 0x32, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x03, 0x75,
+	0x33, 0x32, 0x12, 0x12, 0x0a, 0x03, 0x75, 0x36, 0x34, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x03, 0x75, 0x36, 0x34, 0x12, 0x14, 0x0a, 0x04, 0x75, 0x31, 0x32, 0x38, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x75, 0x31, 0x32, 0x38, 0x12, 0x14, 0x0a, 0x04,
+	0x75, 0x32, 0x35, 0x36, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x75, 0x32,
+	0x35, 0x36, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x05,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x07, 0x62, 0x79, 0x74, 0x65, 0x73, 0x33, 0x32, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x62, 0x79, 0x74, 0x65, 0x73, 0x33, 0x32,
+	0x12, 0x1a, 0x0a, 0x07, 0x62, 0x79, 0x74, 0x65, 0x73, 0x36, 0x35, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0c, 0x48, 0x00, 0x52, 0x07, 0x62, 0x79, 0x74, 0x65, 0x73, 0x36, 0x35, 0x12, 0x2e, 0x0a, 0x06,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72,
+	0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70,
+	0x65, 0x64, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x29, 0x0a, 0x04,
+	0x6c, 0x69, 0x73, 0x74, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6e,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x48,
+	0x00, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0x5b, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x6c, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x65, 0x6c, 0x65, 0x6d, 0x73, 0x22, 0x99, 0x01,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x2e, 0x74, 0x78, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74,
+	0x78, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x06,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x04, 0x6c, 0x69, 0x73,
+	0x74, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x44, 0x0a, 0x0a, 0x53, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x2e, 0x74, 0x78, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22,
+	0x4e, 0x0a, 0x0f, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x2e, 0x74, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x2a,
+	0x6a, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x4e, 0x49, 0x4c, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49,
+	0x4e, 0x47, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x45,
+	0x58, 0x45, 0x43, 0x55, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x4f, 0x4e, 0x45, 0x10, 0x04, 0x2a, 0xdd, 0x01, 0x0a, 0x04,
+	0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0c, 0x0a, 0x08, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4e, 0x49, 0x4c,
+	0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x42, 0x4f, 0x4f, 0x4c, 0x10,
+	0x01, 0x12, 0x0b, 0x0a, 0x07, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x38, 0x10, 0x02, 0x12, 0x0c,
+	0x0a, 0x08, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x31, 0x36, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08,
+	0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x33, 0x32, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08, 0x4b, 0x49,
+	0x4e, 0x44, 0x5f, 0x55, 0x36, 0x34, 0x10, 0x05, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x55, 0x31, 0x32, 0x38, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x55, 0x32, 0x35, 0x36, 0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x53,
+	0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x0a, 0x12, 0x0e, 0x0a, 0x0a, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x0b, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x42, 0x59, 0x54, 0x45, 0x53, 0x33, 0x32, 0x10, 0x0c, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x49, 0x4e,
+	0x44, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x36, 0x35, 0x10, 0x0d, 0x12, 0x0f, 0x0a, 0x0b, 0x4b,
+	0x49, 0x4e, 0x44, 0x5f, 0x53, 0x54, 0x52, 0x55, 0x43, 0x54, 0x10, 0x14, 0x12, 0x0d, 0x0a, 0x09,
+	0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x15, 0x42, 0x1f, 0x5a, 0x1d, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x2f, 0x74, 0x78, 0x2f, 0x74, 0x78, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tx_proto_rawDescOnce sync.Once
+	file_tx_proto_rawDescData = file_tx_proto_rawDesc
+)
+
+func file_tx_proto_rawDescGZIP() []byte {
+	file_tx_proto_rawDescOnce.Do(func() {
+		file_tx_proto_rawDescData = protoimpl.X.CompressGZIP(file_tx_proto_rawDescData)
+	})
+	return file_tx_proto_rawDescData
+}
+
+var file_tx_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_tx_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_tx_proto_goTypes = []interface{}{
+	(Status)(0),             // 0: renproject.tx.Status
+	(Kind)(0),               // 1: renproject.tx.Kind
+	(*Tx)(nil),              // 2: renproject.tx.Tx
+	(*WithStatus)(nil),      // 3: renproject.tx.WithStatus
+	(*Version)(nil),         // 4: renproject.tx.Version
+	(*Selector)(nil),        // 5: renproject.tx.Selector
+	(*Typed)(nil),           // 6: renproject.tx.Typed
+	(*Field)(nil),           // 7: renproject.tx.Field
+	(*Value)(nil),           // 8: renproject.tx.Value
+	(*List)(nil),            // 9: renproject.tx.List
+	(*Type)(nil),            // 10: renproject.tx.Type
+	(*StructType)(nil),      // 11: renproject.tx.StructType
+	(*StructTypeField)(nil), // 12: renproject.tx.StructTypeField
+}
+var file_tx_proto_depIdxs = []int32{
+	4,  // 0: renproject.tx.Tx.version:type_name -> renproject.tx.Version
+	5,  // 1: renproject.tx.Tx.selector:type_name -> renproject.tx.Selector
+	6,  // 2: renproject.tx.Tx.input:type_name -> renproject.tx.Typed
+	6,  // 3: renproject.tx.Tx.output:type_name -> renproject.tx.Typed
+	2,  // 4: renproject.tx.WithStatus.tx:type_name -> renproject.tx.Tx
+	0,  // 5: renproject.tx.WithStatus.status:type_name -> renproject.tx.Status
+	7,  // 6: renproject.tx.Typed.fields:type_name -> renproject.tx.Field
+	8,  // 7: renproject.tx.Field.value:type_name -> renproject.tx.Value
+	6,  // 8: renproject.tx.Value.struct:type_name -> renproject.tx.Typed
+	9,  // 9: renproject.tx.Value.list:type_name -> renproject.tx.List
+	10, // 10: renproject.tx.List.type:type_name -> renproject.tx.Type
+	8,  // 11: renproject.tx.List.elems:type_name -> renproject.tx.Value
+	1,  // 12: renproject.tx.Type.kind:type_name -> renproject.tx.Kind
+	11, // 13: renproject.tx.Type.struct:type_name -> renproject.tx.StructType
+	10, // 14: renproject.tx.Type.list:type_name -> renproject.tx.Type
+	12, // 15: renproject.tx.StructType.fields:type_name -> renproject.tx.StructTypeField
+	10, // 16: renproject.tx.StructTypeField.type:type_name -> renproject.tx.Type
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_tx_proto_init() }
+func file_tx_proto_init() {
+	if File_tx_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_tx_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WithStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Version); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Selector); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Typed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Field); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*List); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Type); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StructType); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StructTypeField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_tx_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*Value_Bool)(nil),
+		(*Value_U8)(nil),
+		(*Value_U16)(nil),
+		(*Value_U32)(nil),
+		(*Value_U64)(nil),
+		(*Value_U128)(nil),
+		(*Value_U256)(nil),
+		(*Value_String_)(nil),
+		(*Value_Bytes)(nil),
+		(*Value_Bytes32)(nil),
+		(*Value_Bytes65)(nil),
+		(*Value_Struct)(nil),
+		(*Value_List)(nil),
+	}
+	file_tx_proto_msgTypes[8].OneofWrappers = []interface{}{
+		(*Type_Kind)(nil),
+		(*Type_Struct)(nil),
+		(*Type_List)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tx_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_tx_proto_goTypes,
+		DependencyIndexes: file_tx_proto_depIdxs,
+		EnumInfos:         file_tx_proto_enumTypes,
+		MessageInfos:      file_tx_proto_msgTypes,
+	}.Build()
+	File_tx_proto = out.File
+	file_tx_proto_rawDesc = nil
+	file_tx_proto_goTypes = nil
+	file_tx_proto_depIdxs = nil
+}
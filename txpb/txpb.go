@@ -0,0 +1,351 @@
+// Package txpb defines protocol buffer types for transactions, and lossless
+// converters between them and the native types defined in the tx package. It
+// exists for services that cannot use surge, and is kept separate from the tx
+// package so that importing transactions does not require importing protocol
+// buffers.
+package txpb
+
+// The generated code is pinned to protoc 3.17.3, and to the version of
+// protoc-gen-go in go.mod, so that regenerating it does not change tx.pb.go.
+// The plugin is built into this directory (it is ignored by git), and removed
+// once the code has been generated.
+//go:generate sh -c "protoc --version | grep -qx 'libprotoc 3.17.3' || { echo 'txpb: protoc 3.17.3 is required' >&2; exit 1; }"
+//go:generate go build -o protoc-gen-go google.golang.org/protobuf/cmd/protoc-gen-go
+//go:generate protoc --plugin=protoc-gen-go=./protoc-gen-go --go_out=. --go_opt=paths=source_relative tx.proto
+//go:generate rm protoc-gen-go
+
+import (
+	"fmt"
+
+	"github.com/renproject/id"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+)
+
+// FromTx converts a native transaction into its protocol buffer equivalent.
+func FromTx(transaction tx.Tx) (*Tx, error) {
+	input, err := FromTyped(transaction.Input)
+	if err != nil {
+		return nil, fmt.Errorf("converting input: %v", err)
+	}
+	output, err := FromTyped(transaction.Output)
+	if err != nil {
+		return nil, fmt.Errorf("converting output: %v", err)
+	}
+	return &Tx{
+		Hash:     transaction.Hash[:],
+		Version:  FromVersion(transaction.Version),
+		Selector: FromSelector(transaction.Selector),
+		Input:    input,
+		Output:   output,
+	}, nil
+}
+
+// ToTx converts a protocol buffer transaction into its native equivalent.
+func ToTx(x *Tx) (tx.Tx, error) {
+	if x == nil {
+		return tx.Tx{}, fmt.Errorf("nil tx")
+	}
+	if len(x.Hash) != id.SizeHintHash {
+		return tx.Tx{}, fmt.Errorf("expected hash len=%v, got len=%v", id.SizeHintHash, len(x.Hash))
+	}
+	input, err := ToTyped(x.Input)
+	if err != nil {
+		return tx.Tx{}, fmt.Errorf("converting input: %v", err)
+	}
+	output, err := ToTyped(x.Output)
+	if err != nil {
+		return tx.Tx{}, fmt.Errorf("converting output: %v", err)
+	}
+	transaction := tx.Tx{
+		Version:  ToVersion(x.Version),
+		Selector: ToSelector(x.Selector),
+		Input:    input,
+		Output:   output,
+	}
+	copy(transaction.Hash[:], x.Hash)
+	return transaction, nil
+}
+
+// FromWithStatus converts a native transaction with a status into its protocol
+// buffer equivalent.
+func FromWithStatus(w tx.WithStatus) (*WithStatus, error) {
+	transaction, err := FromTx(w.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &WithStatus{Tx: transaction, Status: FromStatus(w.Status)}, nil
+}
+
+// ToWithStatus converts a protocol buffer transaction with a status into its
+// native equivalent.
+func ToWithStatus(x *WithStatus) (tx.WithStatus, error) {
+	if x == nil {
+		return tx.WithStatus{}, fmt.Errorf("nil tx with status")
+	}
+	transaction, err := ToTx(x.Tx)
+	if err != nil {
+		return tx.WithStatus{}, err
+	}
+	status, err := ToStatus(x.Status)
+	if err != nil {
+		return tx.WithStatus{}, err
+	}
+	return tx.WithStatus{Tx: transaction, Status: status}, nil
+}
+
+// FromStatus converts a native status into its protocol buffer equivalent.
+func FromStatus(status tx.Status) Status {
+	return Status(status)
+}
+
+// ToStatus converts a protocol buffer status into its native equivalent. An
+// error is returned if the status is not one of the enumerated statuses.
+func ToStatus(status Status) (tx.Status, error) {
+	if status < Status(tx.StatusNil) || status > Status(tx.StatusDone) {
+		return tx.StatusNil, fmt.Errorf("unknown status %v", int32(status))
+	}
+	return tx.Status(status), nil
+}
+
+// FromVersion converts a native version into its protocol buffer equivalent.
+func FromVersion(version tx.Version) *Version {
+	return &Version{Value: string(version)}
+}
+
+// ToVersion converts a protocol buffer version into its native equivalent.
+func ToVersion(x *Version) tx.Version {
+	return tx.Version(x.GetValue())
+}
+
+// FromSelector converts a native selector into its protocol buffer equivalent.
+func FromSelector(selector tx.Selector) *Selector {
+	return &Selector{Value: string(selector)}
+}
+
+// ToSelector converts a protocol buffer selector into its native equivalent.
+func ToSelector(x *Selector) tx.Selector {
+	return tx.Selector(x.GetValue())
+}
+
+// FromTyped converts a well-typed struct into its protocol buffer equivalent.
+// The order of the fields is preserved.
+func FromTyped(typed pack.Typed) (*Typed, error) {
+	fields := make([]*Field, len(typed))
+	for i, field := range typed {
+		value, err := FromValue(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("converting \"%v\": %v", field.Name, err)
+		}
+		fields[i] = &Field{Name: field.Name, Value: value}
+	}
+	return &Typed{Fields: fields}, nil
+}
+
+// ToTyped converts a protocol buffer struct into a well-typed struct. A nil
+// struct is converted into an empty struct.
+func ToTyped(x *Typed) (pack.Typed, error) {
+	typed := make(pack.Typed, len(x.GetFields()))
+	for i, field := range x.GetFields() {
+		value, err := ToValue(field.GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("converting \"%v\": %v", field.GetName(), err)
+		}
+		typed[i] = pack.NewStructField(field.GetName(), value)
+	}
+	return typed, nil
+}
+
+// FromValue converts a value into its protocol buffer equivalent.
+func FromValue(v pack.Value) (*Value, error) {
+	switch v := v.(type) {
+	case pack.Bool:
+		return &Value{Value: &Value_Bool{Bool: bool(v)}}, nil
+	case pack.U8:
+		return &Value{Value: &Value_U8{U8: uint32(v)}}, nil
+	case pack.U16:
+		return &Value{Value: &Value_U16{U16: uint32(v)}}, nil
+	case pack.U32:
+		return &Value{Value: &Value_U32{U32: uint32(v)}}, nil
+	case pack.U64:
+		return &Value{Value: &Value_U64{U64: uint64(v)}}, nil
+	case pack.U128:
+		return &Value{Value: &Value_U128{U128: v.Bytes()}}, nil
+	case pack.U256:
+		return &Value{Value: &Value_U256{U256: v.Bytes()}}, nil
+	case pack.String:
+		return &Value{Value: &Value_String_{String_: string(v)}}, nil
+	case pack.Bytes:
+		return &Value{Value: &Value_Bytes{Bytes: []byte(v)}}, nil
+	case pack.Bytes32:
+		return &Value{Value: &Value_Bytes32{Bytes32: v[:]}}, nil
+	case pack.Bytes65:
+		return &Value{Value: &Value_Bytes65{Bytes65: v[:]}}, nil
+	case pack.Struct:
+		typed, err := FromTyped(pack.Typed(v))
+		if err != nil {
+			return nil, err
+		}
+		return &Value{Value: &Value_Struct{Struct: typed}}, nil
+	case pack.Typed:
+		typed, err := FromTyped(v)
+		if err != nil {
+			return nil, err
+		}
+		return &Value{Value: &Value_Struct{Struct: typed}}, nil
+	case pack.List:
+		t, err := FromType(v.T)
+		if err != nil {
+			return nil, fmt.Errorf("converting list type: %v", err)
+		}
+		elems := make([]*Value, len(v.Elems))
+		for i := range v.Elems {
+			if elems[i], err = FromValue(v.Elems[i]); err != nil {
+				return nil, fmt.Errorf("converting list element %v: %v", i, err)
+			}
+		}
+		return &Value{Value: &Value_List{List: &List{Type: t, Elems: elems}}}, nil
+	default:
+		return nil, fmt.Errorf("non-exhaustive pattern: value %T", v)
+	}
+}
+
+// ToValue converts a protocol buffer value into its native equivalent.
+func ToValue(x *Value) (pack.Value, error) {
+	switch x := x.GetValue().(type) {
+	case *Value_Bool:
+		return pack.NewBool(x.Bool), nil
+	case *Value_U8:
+		if x.U8 > 0xFF {
+			return nil, fmt.Errorf("u8 overflow: %v", x.U8)
+		}
+		return pack.NewU8(uint8(x.U8)), nil
+	case *Value_U16:
+		if x.U16 > 0xFFFF {
+			return nil, fmt.Errorf("u16 overflow: %v", x.U16)
+		}
+		return pack.NewU16(uint16(x.U16)), nil
+	case *Value_U32:
+		return pack.NewU32(x.U32), nil
+	case *Value_U64:
+		return pack.NewU64(x.U64), nil
+	case *Value_U128:
+		b16 := [16]byte{}
+		if len(x.U128) != len(b16) {
+			return nil, fmt.Errorf("expected u128 len=%v, got len=%v", len(b16), len(x.U128))
+		}
+		copy(b16[:], x.U128)
+		return pack.NewU128(b16), nil
+	case *Value_U256:
+		b32 := [32]byte{}
+		if len(x.U256) != len(b32) {
+			return nil, fmt.Errorf("expected u256 len=%v, got len=%v", len(b32), len(x.U256))
+		}
+		copy(b32[:], x.U256)
+		return pack.NewU256(b32), nil
+	case *Value_String_:
+		return pack.NewString(x.String_), nil
+	case *Value_Bytes:
+		return pack.NewBytes(x.Bytes), nil
+	case *Value_Bytes32:
+		b32 := pack.Bytes32{}
+		if len(x.Bytes32) != len(b32) {
+			return nil, fmt.Errorf("expected bytes32 len=%v, got len=%v", len(b32), len(x.Bytes32))
+		}
+		copy(b32[:], x.Bytes32)
+		return b32, nil
+	case *Value_Bytes65:
+		b65 := pack.Bytes65{}
+		if len(x.Bytes65) != len(b65) {
+			return nil, fmt.Errorf("expected bytes65 len=%v, got len=%v", len(b65), len(x.Bytes65))
+		}
+		copy(b65[:], x.Bytes65)
+		return b65, nil
+	case *Value_Struct:
+		typed, err := ToTyped(x.Struct)
+		if err != nil {
+			return nil, err
+		}
+		return pack.Struct(typed), nil
+	case *Value_List:
+		t, err := ToType(x.List.GetType())
+		if err != nil {
+			return nil, fmt.Errorf("converting list type: %v", err)
+		}
+		list := pack.List{T: t, Elems: make([]pack.Value, len(x.List.GetElems()))}
+		for i, elem := range x.List.GetElems() {
+			if list.Elems[i], err = ToValue(elem); err != nil {
+				return nil, fmt.Errorf("converting list element %v: %v", i, err)
+			}
+			if !list.Elems[i].Type().Equals(t) {
+				return nil, fmt.Errorf("inconsistent list type: expected %v, got %v", t, list.Elems[i].Type())
+			}
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("non-exhaustive pattern: value %T", x)
+	}
+}
+
+// FromType converts a type into its protocol buffer equivalent.
+func FromType(t pack.Type) (*Type, error) {
+	def, err := tx.NewTypeDef(t)
+	if err != nil {
+		return nil, err
+	}
+	return fromTypeDef(def), nil
+}
+
+func fromTypeDef(def tx.TypeDef) *Type {
+	switch def.Kind {
+	case pack.KindStruct:
+		fields := make([]*StructTypeField, len(def.Fields))
+		for i, field := range def.Fields {
+			fields[i] = &StructTypeField{Name: field.Name, Type: fromTypeDef(field.Type)}
+		}
+		return &Type{Type: &Type_Struct{Struct: &StructType{Fields: fields}}}
+	case pack.KindList:
+		return &Type{Type: &Type_List{List: fromTypeDef(*def.Elem)}}
+	default:
+		return &Type{Type: &Type_Kind{Kind: Kind(def.Kind)}}
+	}
+}
+
+// ToType converts a protocol buffer type into its native equivalent.
+func ToType(x *Type) (pack.Type, error) {
+	def, err := toTypeDef(x)
+	if err != nil {
+		return nil, err
+	}
+	return def.PackType()
+}
+
+func toTypeDef(x *Type) (tx.TypeDef, error) {
+	switch x := x.GetType().(type) {
+	case *Type_Kind:
+		// Struct and list types must define their fields and elements. Other
+		// kinds that are not scalar are rejected by PackType.
+		if kind := pack.Kind(x.Kind); kind != pack.KindStruct && kind != pack.KindList {
+			return tx.TypeDef{Kind: kind}, nil
+		}
+		return tx.TypeDef{}, fmt.Errorf("unexpected kind %v", x.Kind)
+	case *Type_Struct:
+		def := tx.TypeDef{Kind: pack.KindStruct, Fields: make([]tx.TypeDefField, len(x.Struct.GetFields()))}
+		for i, field := range x.Struct.GetFields() {
+			fieldDef, err := toTypeDef(field.GetType())
+			if err != nil {
+				return tx.TypeDef{}, fmt.Errorf("converting \"%v\": %v", field.GetName(), err)
+			}
+			def.Fields[i] = tx.TypeDefField{Name: field.GetName(), Type: fieldDef}
+		}
+		return def, nil
+	case *Type_List:
+		elem, err := toTypeDef(x.List)
+		if err != nil {
+			return tx.TypeDef{}, err
+		}
+		return tx.TypeDef{Kind: pack.KindList, Elem: &elem}, nil
+	default:
+		return tx.TypeDef{}, fmt.Errorf("non-exhaustive pattern: type %T", x)
+	}
+}
@@ -0,0 +1,13 @@
+package txpb_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTxpb(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Txpb Suite")
+}
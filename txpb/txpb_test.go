@@ -0,0 +1,195 @@
+package txpb_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txpb"
+	"github.com/renproject/tx/txutil"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Protocol buffers", func() {
+
+	roundTrip := func(transaction tx.Tx) tx.Tx {
+		x, err := txpb.FromTx(transaction)
+		Expect(err).ToNot(HaveOccurred())
+		data, err := proto.Marshal(x)
+		Expect(err).ToNot(HaveOccurred())
+		y := new(txpb.Tx)
+		Expect(proto.Unmarshal(data, y)).To(Succeed())
+		other, err := txpb.ToTx(y)
+		Expect(err).ToNot(HaveOccurred())
+		return other
+	}
+
+	Context("when converting and then converting back", func() {
+		It("should preserve the transaction hash", func() {
+			f := func(seed int64) bool {
+				transaction := txutil.RandomGoodTx(rand.New(rand.NewSource(seed)))
+				other := roundTrip(transaction)
+				Expect(other.Hash).To(Equal(transaction.Hash))
+
+				hash, err := tx.NewTxHash(other.Version, other.Selector, other.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hash).To(Equal(transaction.Hash))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should preserve the binary encoding of random transactions", func() {
+			f := func(seed int64) bool {
+				r := rand.New(rand.NewSource(seed))
+				value, _ := quick.Value(reflect.TypeOf(tx.Tx{}), r)
+				transaction := value.Interface().(tx.Tx)
+				other := roundTrip(transaction)
+
+				expected, err := surge.ToBinary(transaction)
+				Expect(err).ToNot(HaveOccurred())
+				got, err := surge.ToBinary(other)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(got).To(Equal(expected))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+
+		It("should preserve nested structs and empty lists", func() {
+			inner := pack.NewStruct(
+				"u128", pack.NewU128FromUint64(42),
+				"bytes65", pack.Bytes65{1, 2, 3},
+				"empty", pack.EmptyList(pack.NewStruct("a", pack.NewU8(1), "b", pack.EmptyList(pack.NewString("").Type())).Type()),
+			)
+			list, err := pack.NewList(pack.NewU16(1), pack.NewU16(2))
+			Expect(err).ToNot(HaveOccurred())
+			transaction, err := tx.NewTx("BTC/toEthereum", pack.NewTyped(
+				"inner", inner,
+				"list", list,
+				"bool", pack.NewBool(true),
+			))
+			Expect(err).ToNot(HaveOccurred())
+			other := roundTrip(transaction)
+
+			hash, err := tx.NewTxHash(other.Version, other.Selector, other.Input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hash).To(Equal(transaction.Hash))
+			Expect(other.Input.String()).To(Equal(transaction.Input.String()))
+		})
+
+		It("should preserve the status", func() {
+			f := func(seed int64) bool {
+				w := txutil.RandomGoodTxWithStatus(rand.New(rand.NewSource(seed)))
+				x, err := txpb.FromWithStatus(w)
+				Expect(err).ToNot(HaveOccurred())
+				data, err := proto.Marshal(x)
+				Expect(err).ToNot(HaveOccurred())
+				y := new(txpb.WithStatus)
+				Expect(proto.Unmarshal(data, y)).To(Succeed())
+				other, err := txpb.ToWithStatus(y)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(other.Status).To(Equal(w.Status))
+				Expect(other.Hash).To(Equal(w.Hash))
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when converting nil inputs and outputs", func() {
+		It("should return empty, non-nil inputs and outputs", func() {
+			typed, err := txpb.ToTyped(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(typed).ToNot(BeNil())
+			Expect(typed).To(BeEmpty())
+
+			other, err := txpb.ToTx(&txpb.Tx{Hash: make([]byte, 32)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(other.Input).ToNot(BeNil())
+			Expect(other.Input).To(BeEmpty())
+			Expect(other.Output).ToNot(BeNil())
+			Expect(other.Output).To(BeEmpty())
+		})
+	})
+
+	Context("when converting types", func() {
+		It("should preserve the type", func() {
+			for _, t := range []pack.Type{
+				pack.NewU64(0).Type(),
+				pack.NewStruct("b", pack.NewBool(false), "a", pack.EmptyList(pack.Bytes32{}.Type())).Type(),
+				pack.EmptyList(pack.EmptyList(pack.NewStruct().Type()).Type()).Type(),
+			} {
+				x, err := txpb.FromType(t)
+				Expect(err).ToNot(HaveOccurred())
+				other, err := txpb.ToType(x)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(other.Equals(t)).To(BeTrue(), "%v", t)
+			}
+		})
+
+		It("should preserve the order of struct fields", func() {
+			x, err := txpb.FromType(pack.NewStruct("b", pack.NewBool(false), "a", pack.NewU8(0)).Type())
+			Expect(err).ToNot(HaveOccurred())
+			fields := x.GetStruct().GetFields()
+			Expect(fields).To(HaveLen(2))
+			Expect(fields[0].GetName()).To(Equal("b"))
+			Expect(fields[0].GetType().GetKind()).To(Equal(txpb.Kind(pack.KindBool)))
+			Expect(fields[1].GetName()).To(Equal("a"))
+		})
+
+		It("should return an error for malformed types", func() {
+			_, err := txpb.FromType(nil)
+			Expect(err).To(HaveOccurred())
+			for _, x := range []*txpb.Type{
+				nil,
+				{},
+				{Type: &txpb.Type_Kind{Kind: txpb.Kind(pack.KindNil)}},
+				{Type: &txpb.Type_Kind{Kind: txpb.Kind(pack.KindStruct)}},
+				{Type: &txpb.Type_Kind{Kind: txpb.Kind(pack.KindList)}},
+				{Type: &txpb.Type_Kind{Kind: 99}},
+				{Type: &txpb.Type_List{}},
+			} {
+				_, err := txpb.ToType(x)
+				Expect(err).To(HaveOccurred(), x.String())
+			}
+		})
+	})
+
+	Context("when converting a malformed transaction", func() {
+		It("should return an error", func() {
+			_, err := txpb.ToTx(nil)
+			Expect(err).To(HaveOccurred())
+			_, err = txpb.ToTx(&txpb.Tx{Hash: []byte{1, 2, 3}})
+			Expect(err).To(HaveOccurred())
+			_, err = txpb.ToTx(&txpb.Tx{
+				Hash: make([]byte, 32),
+				Input: &txpb.Typed{Fields: []*txpb.Field{
+					{Name: "amount", Value: &txpb.Value{Value: &txpb.Value_U256{U256: []byte{1}}}},
+				}},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for unknown statuses", func() {
+			for _, status := range []txpb.Status{-1, txpb.Status(tx.StatusDone) + 1, 256, math.MaxInt32, math.MinInt32} {
+				_, err := txpb.ToStatus(status)
+				Expect(err).To(HaveOccurred(), "status %v", int32(status))
+				_, err = txpb.ToWithStatus(&txpb.WithStatus{Tx: &txpb.Tx{Hash: make([]byte, 32)}, Status: status})
+				Expect(err).To(HaveOccurred(), "status %v", int32(status))
+			}
+			for status := tx.StatusNil; status <= tx.StatusDone; status++ {
+				converted, err := txpb.ToStatus(txpb.FromStatus(status))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(converted).To(Equal(status))
+			}
+		})
+	})
+})
@@ -0,0 +1,226 @@
+package txutil
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+)
+
+// An InvalidTxCategory is a specific way in which a transaction can be
+// malformed.
+type InvalidTxCategory string
+
+// Enumeration of all invalid transaction categories.
+const (
+	InvalidTxHash           = InvalidTxCategory("wrong hash")
+	InvalidTxVersion        = InvalidTxCategory("unknown version")
+	InvalidTxAsset          = InvalidTxCategory("unknown asset")
+	InvalidTxSameChain      = InvalidTxCategory("same source and destination")
+	InvalidTxMissingInput   = InvalidTxCategory("missing input")
+	InvalidTxInputType      = InvalidTxCategory("wrong input type")
+	InvalidTxPayloadSize    = InvalidTxCategory("oversized payload")
+	InvalidTxNHash          = InvalidTxCategory("mismatched nhash")
+	InvalidTxRecipient      = InvalidTxCategory("invalid recipient")
+	InvalidTxUnsupportedFn  = InvalidTxCategory("unsupported function")
+	InvalidTxIntrinsicInput = InvalidTxCategory("invalid intrinsic input")
+)
+
+// InvalidTxCategories returns all invalid transaction categories.
+func InvalidTxCategories() []InvalidTxCategory {
+	return []InvalidTxCategory{
+		InvalidTxHash,
+		InvalidTxVersion,
+		InvalidTxAsset,
+		InvalidTxSameChain,
+		InvalidTxMissingInput,
+		InvalidTxInputType,
+		InvalidTxPayloadSize,
+		InvalidTxNHash,
+		InvalidTxRecipient,
+		InvalidTxUnsupportedFn,
+		InvalidTxIntrinsicInput,
+	}
+}
+
+// Err returns the error that tx.Tx.Validate is expected to wrap for
+// transactions in the category. Oversized payloads are not checked by
+// tx.Tx.Validate, so for InvalidTxPayloadSize it is the error that
+// tx.Tx.CheckPolicy is expected to wrap for tx.DefaultPolicy (see
+// InvalidTx.Check).
+func (category InvalidTxCategory) Err() error {
+	switch category {
+	case InvalidTxHash:
+		return tx.ErrInvalidHash
+	case InvalidTxVersion:
+		return tx.ErrUnknownVersion
+	case InvalidTxAsset:
+		return tx.ErrUnknownAsset
+	case InvalidTxSameChain:
+		return tx.ErrSameChain
+	case InvalidTxMissingInput:
+		return tx.ErrMissingInput
+	case InvalidTxInputType, InvalidTxIntrinsicInput:
+		return tx.ErrInvalidInputType
+	case InvalidTxPayloadSize:
+		return tx.ErrPayloadTooLarge
+	case InvalidTxNHash:
+		return tx.ErrInvalidNHash
+	case InvalidTxRecipient:
+		return tx.ErrInvalidRecipient
+	case InvalidTxUnsupportedFn:
+		return tx.ErrUnsupportedSelector
+	default:
+		return nil
+	}
+}
+
+// An InvalidTx is a transaction that is malformed in one specific way. Apart
+// from the malformation, the transaction is valid, and its hash is correct
+// (unless the malformation is the hash).
+type InvalidTx struct {
+	Tx       tx.Tx
+	Category InvalidTxCategory
+	// Err is the error that tx.Tx.Validate (or tx.Tx.CheckPolicy, for
+	// oversized payloads) is expected to wrap.
+	Err error
+}
+
+// Check returns the error that is expected to wrap Err. It is the error
+// returned by tx.Tx.CheckPolicy for tx.DefaultPolicy for oversized payloads,
+// and the error returned by tx.Tx.Validate for the registry otherwise.
+func (invalid InvalidTx) Check(registry tx.NetworkRegistry) error {
+	if invalid.Category == InvalidTxPayloadSize {
+		return invalid.Tx.CheckPolicy(tx.DefaultPolicy())
+	}
+	return invalid.Tx.Validate(registry)
+}
+
+// RandomValidTx returns a random cross-chain transaction with coherent inputs,
+// for one of AllSelectors. It passes tx.Tx.Validate for DefaultNetworkRegistry.
+func RandomValidTx(r *rand.Rand) tx.Tx {
+	selector := RandomGoodTxSelector(r)
+	var transaction tx.Tx
+	var err error
+	if selector.IsLock() {
+		transaction, err = newLockMint(r, selector.Asset(), selector.Destination(), RandomAmount(r))
+	} else {
+		transaction, err = newBurn(r, selector, RandomAmount(r))
+	}
+	if err != nil {
+		panic(err)
+	}
+	return transaction
+}
+
+// RandomInvalidTx returns a random transaction from a random category.
+func RandomInvalidTx(r *rand.Rand) InvalidTx {
+	categories := InvalidTxCategories()
+	return NewInvalidTx(r, categories[r.Intn(len(categories))])
+}
+
+// RandomInvalidTxs returns n random invalid transactions, cycling through all
+// categories.
+func RandomInvalidTxs(r *rand.Rand, n int) []InvalidTx {
+	categories := InvalidTxCategories()
+	txs := make([]InvalidTx, n)
+	for i := range txs {
+		txs[i] = NewInvalidTx(r, categories[i%len(categories)])
+	}
+	return txs
+}
+
+// NewInvalidTx returns a random transaction that is malformed in the way that
+// is described by the category. It panics if the category is unknown.
+func NewInvalidTx(r *rand.Rand, category InvalidTxCategory) InvalidTx {
+	transaction := RandomValidTx(r)
+	switch category {
+	case InvalidTxHash:
+		transaction.Hash[r.Intn(len(transaction.Hash))] ^= byte(1 + r.Intn(255))
+		return InvalidTx{Tx: transaction, Category: category, Err: category.Err()}
+	case InvalidTxVersion:
+		transaction.Version = tx.Version(fmt.Sprintf("%v", 2+r.Intn(100)))
+	case InvalidTxAsset:
+		transaction.Selector = tx.Selector(fmt.Sprintf("X%v/%v", transaction.Selector.Asset(), transaction.Selector.Fn()))
+	case InvalidTxSameChain:
+		asset, host := transaction.Selector.Asset(), transaction.Selector.Destination()
+		if r.Int()%2 == 0 {
+			transaction.Selector = tx.Selector(fmt.Sprintf("%v/to%v", asset, asset.OriginChain()))
+		} else {
+			transaction.Selector = tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, host))
+		}
+	case InvalidTxMissingInput:
+		fields := tx.CrossChainInputTypes()
+		transaction.Input = removeInput(transaction.Input, fields[r.Intn(len(fields))].Name)
+	case InvalidTxInputType:
+		fields := tx.CrossChainInputTypes()
+		field := fields[r.Intn(len(fields))]
+		transaction.Input = setInput(transaction.Input, field.Name, wrongType(r, field.Value))
+	case InvalidTxPayloadSize:
+		payload := randomBytes(r, tx.MaxPayloadBytes+1+r.Intn(1024))
+		transaction.Input = setInput(transaction.Input, "payload", pack.NewBytes(payload))
+		transaction.Input = setInput(transaction.Input, "phash", tx.NewPHash(payload))
+	case InvalidTxNHash:
+		transaction.Input = setInput(transaction.Input, "nhash", pack.NewBytes32(randomBytes32(r)))
+	case InvalidTxRecipient:
+		transaction.Input = setInput(transaction.Input, "to", pack.String(fmt.Sprintf("invalid-%x", randomBytes(r, r.Intn(20)))))
+	case InvalidTxUnsupportedFn:
+		// Functions that do not exist on the network. The extrinsic functions
+		// (such as tx.ClaimFeesFn) are supported.
+		fns := []string{"transfer", "approve", "swap"}
+		transaction.Selector = tx.Selector(fmt.Sprintf("%v/%v", transaction.Selector.Asset(), fns[r.Intn(len(fns))]))
+	case InvalidTxIntrinsicInput:
+		fns := tx.IntrinsicSelectors
+		transaction.Selector = tx.Selector(fmt.Sprintf("%v/%v", transaction.Selector.Asset(), fns[r.Intn(len(fns))]))
+	default:
+		panic(fmt.Sprintf("unknown invalid tx category %q", category))
+	}
+
+	// Only the hash of transactions in the InvalidTxHash category is wrong.
+	hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+	if err != nil {
+		panic(err)
+	}
+	transaction.Hash = hash
+	return InvalidTx{Tx: transaction, Category: category, Err: category.Err()}
+}
+
+// removeInput returns a copy of an input without a field.
+func removeInput(input pack.Typed, name string) pack.Typed {
+	removed := make(pack.Typed, 0, len(input))
+	for _, field := range input {
+		if field.Name != name {
+			removed = append(removed, field)
+		}
+	}
+	return removed
+}
+
+// setInput returns a copy of an input with the value of a field replaced.
+func setInput(input pack.Typed, name string, value pack.Value) pack.Typed {
+	replaced := make(pack.Typed, len(input))
+	copy(replaced, input)
+	for i := range replaced {
+		if replaced[i].Name == name {
+			replaced[i] = pack.NewStructField(name, value)
+		}
+	}
+	return replaced
+}
+
+// wrongType returns a random value with a different type to the value.
+func wrongType(r *rand.Rand, value pack.Value) pack.Value {
+	candidates := []pack.Value{
+		pack.NewU64(r.Uint64()),
+		pack.String(fmt.Sprintf("%x", r.Uint64())),
+		pack.NewBytes(randomBytes(r, 8)),
+		pack.NewBool(true),
+	}
+	for {
+		candidate := candidates[r.Intn(len(candidates))]
+		if candidate.Type().Kind() != value.Type().Kind() {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package txutil_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invalid transactions", func() {
+
+	registry := txutil.DefaultNetworkRegistry()
+
+	// allErrs are all of the errors that validation can wrap.
+	allErrs := []error{
+		tx.ErrUnknownVersion,
+		tx.ErrUnknownAsset,
+		tx.ErrSameChain,
+		tx.ErrUnsupportedSelector,
+		tx.ErrMissingInput,
+		tx.ErrInvalidInputType,
+		tx.ErrPayloadTooLarge,
+		tx.ErrInvalidRecipient,
+		tx.ErrInvalidNHash,
+		tx.ErrInvalidHash,
+	}
+
+	Context("when generating valid transactions", func() {
+		It("should pass validation", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				Expect(txutil.RandomValidTx(r).Validate(registry)).To(Succeed())
+			}
+		})
+	})
+
+	Context("when generating invalid transactions", func() {
+		It("should tag every category with an error", func() {
+			for _, category := range txutil.InvalidTxCategories() {
+				Expect(category.Err()).ToNot(BeNil(), "category %v", category)
+			}
+			Expect(txutil.InvalidTxCategory("unknown").Err()).To(BeNil())
+		})
+
+		It("should trigger only the tagged error", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for _, invalid := range txutil.RandomInvalidTxs(r, 10*len(txutil.InvalidTxCategories())) {
+				Expect(invalid.Err).To(Equal(invalid.Category.Err()))
+				err := invalid.Check(registry)
+				Expect(err).To(HaveOccurred())
+				for _, other := range allErrs {
+					Expect(errors.Is(err, other)).To(Equal(other == invalid.Err), "category %v: %v", invalid.Category, err)
+				}
+			}
+		})
+
+		It("should have a correct hash unless the hash is malformed", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				invalid := txutil.RandomInvalidTx(r)
+				hash, err := tx.NewTxHash(invalid.Tx.Version, invalid.Tx.Selector, invalid.Tx.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(invalid.Tx.Hash == hash).To(Equal(invalid.Category != txutil.InvalidTxHash))
+			}
+		})
+
+		It("should only check the size of payloads with a policy", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			invalid := txutil.NewInvalidTx(r, txutil.InvalidTxPayloadSize)
+			Expect(invalid.Tx.Validate(registry)).To(Succeed())
+			Expect(errors.Is(invalid.Tx.CheckPolicy(tx.DefaultPolicy()), tx.ErrPayloadTooLarge)).To(BeTrue())
+			Expect(errors.Is(invalid.Check(registry), tx.ErrPayloadTooLarge)).To(BeTrue())
+		})
+
+		It("should only use functions that do not exist for unsupported functions", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				selector := txutil.NewInvalidTx(r, txutil.InvalidTxUnsupportedFn).Tx.Selector
+				Expect(selector.IsClaimFees() || selector.IsClaimFeesFromEvent() || selector.IsReturnStateAndOutputs() || selector.IsIntrinsic()).To(BeFalse(), "selector %v", selector)
+			}
+		})
+
+		It("should panic for unknown categories", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			Expect(func() { txutil.NewInvalidTx(r, "unknown") }).To(Panic())
+		})
+	})
+})
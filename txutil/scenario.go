@@ -0,0 +1,400 @@
+package txutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+)
+
+// A Scenario is a realistic sequence of transactions, such as a lock-and-mint
+// followed by a burn-and-release of the same amount. Unlike the random
+// transactions in this package, the inputs of transactions in a scenario are
+// coherent: hashes are derived from the other inputs, recipients are valid
+// addresses, and amounts match across steps.
+type Scenario struct {
+	// Name describes the scenario.
+	Name string
+	// Steps are the transactions of the scenario, in the order that they are
+	// submitted.
+	Steps []ScenarioStep
+}
+
+// A ScenarioStep is a transaction in a scenario, and the statuses that it is
+// expected to move through.
+type ScenarioStep struct {
+	Tx tx.Tx
+	// Statuses are the expected statuses of the transaction, in order. The
+	// last status is its final status. A transaction that is expected to be
+	// rejected has the single status tx.StatusNil.
+	Statuses []tx.Status
+}
+
+// Txs returns the transactions of the scenario, in order.
+func (scenario Scenario) Txs() []tx.Tx {
+	txs := make([]tx.Tx, len(scenario.Steps))
+	for i := range scenario.Steps {
+		txs[i] = scenario.Steps[i].Tx
+	}
+	return txs
+}
+
+// WithStatuses returns the transaction once for each of its expected statuses.
+func (step ScenarioStep) WithStatuses() []tx.WithStatus {
+	txs := make([]tx.WithStatus, len(step.Statuses))
+	for i, status := range step.Statuses {
+		txs[i] = tx.WithStatus{Tx: step.Tx, Status: status}
+	}
+	return txs
+}
+
+// CrossChainStatuses returns the statuses of a cross-chain transaction that
+// succeeds: it waits for confirmations on its source chain, waits in the pool,
+// is executed, and is done.
+func CrossChainStatuses() []tx.Status {
+	return []tx.Status{tx.StatusConfirming, tx.StatusPending, tx.StatusExecuting, tx.StatusDone}
+}
+
+// IntrinsicStatuses returns the statuses of an intrinsic transaction. These
+// are proposed directly into blocks, so they are never confirming or pending.
+func IntrinsicStatuses() []tx.Status {
+	return []tx.Status{tx.StatusExecuting, tx.StatusDone}
+}
+
+// RejectedStatuses returns the statuses of a transaction that is rejected.
+func RejectedStatuses() []tx.Status {
+	return []tx.Status{tx.StatusNil}
+}
+
+// NewLockMintScenario returns a scenario in which an asset is locked on its
+// origin chain and minted to a host chain. The phash, nhash, and ghash of the
+// transaction are derived from its other inputs, and the gpubkey is a valid
+// compressed secp256k1 public key.
+func NewLockMintScenario(r *rand.Rand, asset multichain.Asset, host multichain.Chain) (Scenario, error) {
+	mint, err := newLockMint(r, asset, host, RandomAmount(r))
+	if err != nil {
+		return Scenario{}, err
+	}
+	return Scenario{
+		Name:  fmt.Sprintf("lock %v and mint to %v", asset, host),
+		Steps: []ScenarioStep{{Tx: mint, Statuses: CrossChainStatuses()}},
+	}, nil
+}
+
+// NewRoundTripScenario returns a scenario in which an asset is locked on its
+// origin chain and minted to a host chain, and then the minted amount is burned
+// from the host chain and released back to the origin chain. The minted amount
+// is the locked amount minus the fees in the schedule (see tx.EstimateFees).
+func NewRoundTripScenario(r *rand.Rand, asset multichain.Asset, host multichain.Chain, fees tx.FeeSchedule) (Scenario, error) {
+	amount := RandomAmount(r)
+	mint, err := newLockMint(r, asset, host, amount)
+	if err != nil {
+		return Scenario{}, err
+	}
+	locked, err := tx.NewAmount(amount, asset)
+	if err != nil {
+		return Scenario{}, err
+	}
+	estimate, err := tx.EstimateFees(mint.Selector, locked, fees)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("estimating fees: %v", err)
+	}
+	release, err := newBurn(r, tx.Selector(fmt.Sprintf("%v/from%v", asset, host)), estimate.Net.Value)
+	if err != nil {
+		return Scenario{}, err
+	}
+	return Scenario{
+		Name: fmt.Sprintf("lock %v, mint to %v, and burn to release", asset, host),
+		Steps: []ScenarioStep{
+			{Tx: mint, Statuses: CrossChainStatuses()},
+			{Tx: release, Statuses: CrossChainStatuses()},
+		},
+	}, nil
+}
+
+// NewBurnMintScenario returns a scenario in which an asset is locked on its
+// origin chain and minted to one host chain, and then the minted amount is
+// burned from that host chain and minted to another. Like in
+// NewRoundTripScenario, the minted amount is the locked amount minus the fees
+// in the schedule.
+func NewBurnMintScenario(r *rand.Rand, asset multichain.Asset, from, to multichain.Chain, fees tx.FeeSchedule) (Scenario, error) {
+	amount := RandomAmount(r)
+	mint, err := newLockMint(r, asset, from, amount)
+	if err != nil {
+		return Scenario{}, err
+	}
+	locked, err := tx.NewAmount(amount, asset)
+	if err != nil {
+		return Scenario{}, err
+	}
+	estimate, err := tx.EstimateFees(mint.Selector, locked, fees)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("estimating fees: %v", err)
+	}
+	burnMint, err := newBurn(r, tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, to, from)), estimate.Net.Value)
+	if err != nil {
+		return Scenario{}, err
+	}
+	return Scenario{
+		Name: fmt.Sprintf("lock %v, mint to %v, and burn to mint to %v", asset, from, to),
+		Steps: []ScenarioStep{
+			{Tx: mint, Statuses: CrossChainStatuses()},
+			{Tx: burnMint, Statuses: CrossChainStatuses()},
+		},
+	}, nil
+}
+
+// NewDuplicateDepositScenario returns a scenario in which a deposit is minted
+// to a host chain, and then the same deposit is submitted again with a
+// different payload and recipient. Both transactions have the same nhash, so
+// the second transaction is rejected, even though it has a different hash.
+func NewDuplicateDepositScenario(r *rand.Rand, asset multichain.Asset, host multichain.Chain) (Scenario, error) {
+	mint, err := newLockMint(r, asset, host, RandomAmount(r))
+	if err != nil {
+		return Scenario{}, err
+	}
+	to := RandomAddress(r, host)
+	payload := randomBytes(r, 1+r.Intn(64))
+	nonce := mint.Input.Get("nonce").(pack.Bytes32)
+	phash := tx.NewPHash(payload)
+	shash := tx.NewMintSelectorHash(asset, host)
+	duplicate, err := newTx(mint.Selector, pack.NewTyped(
+		"txid", mint.Input.Get("txid"),
+		"txindex", mint.Input.Get("txindex"),
+		"amount", mint.Input.Get("amount"),
+		"payload", pack.NewBytes(payload),
+		"phash", phash,
+		"to", pack.String(to),
+		"nonce", nonce,
+		"nhash", mint.Input.Get("nhash"),
+		"gpubkey", mint.Input.Get("gpubkey"),
+		"ghash", tx.NewGHash(phash, shash, rawAddress(host, to), nonce),
+	))
+	if err != nil {
+		return Scenario{}, err
+	}
+	return Scenario{
+		Name: fmt.Sprintf("lock %v and mint to %v twice", asset, host),
+		Steps: []ScenarioStep{
+			{Tx: mint, Statuses: CrossChainStatuses()},
+			{Tx: duplicate, Statuses: RejectedStatuses()},
+		},
+	}, nil
+}
+
+// NewEpochScenario returns a scenario in which a contract begins a new epoch,
+// using an epoch intrinsic transaction.
+func NewEpochScenario(r *rand.Rand, contract string, number uint64) (Scenario, error) {
+	epoch, err := tx.NewEpochTx(contract, tx.EpochInput{
+		Number: pack.NewU64(number),
+		Hash:   pack.NewBytes32(randomBytes32(r)),
+	})
+	if err != nil {
+		return Scenario{}, err
+	}
+	epoch.Output = pack.NewTyped()
+	return Scenario{
+		Name:  fmt.Sprintf("begin epoch %v of %v", number, contract),
+		Steps: []ScenarioStep{{Tx: epoch, Statuses: IntrinsicStatuses()}},
+	}, nil
+}
+
+// RandomScenario returns a random scenario for a random asset and host chains
+// that are supported by DefaultNetworkRegistry.
+func RandomScenario(r *rand.Rand) Scenario {
+	assets, hosts := SupportedAssets(), SupportedHostChains()
+	asset := assets[r.Intn(len(assets))]
+	host := hosts[r.Intn(len(hosts))]
+	otherHost := hosts[r.Intn(len(hosts)-1)]
+	if otherHost == host {
+		otherHost = hosts[len(hosts)-1]
+	}
+
+	var scenario Scenario
+	var err error
+	switch r.Int() % 5 {
+	case 0:
+		scenario, err = NewLockMintScenario(r, asset, host)
+	case 1:
+		scenario, err = NewRoundTripScenario(r, asset, host, randomFeeSchedule(r, asset, host))
+	case 2:
+		scenario, err = NewBurnMintScenario(r, asset, host, otherHost, randomFeeSchedule(r, asset, host))
+	case 3:
+		scenario, err = NewDuplicateDepositScenario(r, asset, host)
+	default:
+		scenario, err = NewEpochScenario(r, string(asset), uint64(r.Int63()))
+	}
+	if err != nil {
+		panic(err)
+	}
+	return scenario
+}
+
+// randomFeeSchedule returns a fee schedule with random underlying fees for the
+// origin chain of the asset and the host chain, and random mint and burn fees
+// for the host chain, that are small enough to be covered by RandomAmount.
+func randomFeeSchedule(r *rand.Rand, asset multichain.Asset, host multichain.Chain) tx.FeeSchedule {
+	return tx.FeeSchedule{
+		Asset: asset,
+		Chains: []tx.ChainFees{{
+			Chain:         asset.OriginChain(),
+			UnderlyingFee: pack.NewU256FromUint64(uint64(r.Intn(1000))),
+		}, {
+			Chain:         host,
+			UnderlyingFee: pack.NewU256FromUint64(uint64(r.Intn(1000))),
+			MintFee:       pack.NewU16(uint16(r.Intn(100))),
+			BurnFee:       pack.NewU16(uint16(r.Intn(100))),
+		}},
+	}
+}
+
+// RandomAmount returns a random amount of an asset, in its smallest unit, that
+// is large enough to cover fees.
+func RandomAmount(r *rand.Rand) pack.U256 {
+	return pack.NewU256FromUint64(10000 + uint64(r.Int63n(100000000)))
+}
+
+// RandomAddress returns a random address on a chain, in the canonical form
+// returned by tx.NormaliseAddress. It panics if addresses on the chain are not
+// supported.
+func RandomAddress(r *rand.Rand, chain multichain.Chain) string {
+	hash := randomBytes(r, 20)
+	var addr string
+	switch chain {
+	case multichain.Bitcoin, multichain.BitcoinCash:
+		addr = base58.CheckEncode(hash, 0x00)
+	case multichain.DigiByte, multichain.Dogecoin:
+		addr = base58.CheckEncode(hash, 0x1e)
+	case multichain.Zcash:
+		payload := append([]byte{0x1c, 0xb8}, hash...)
+		first := sha256.Sum256(payload)
+		second := sha256.Sum256(first[:])
+		addr = base58.Encode(append(payload, second[:4]...))
+	case multichain.Filecoin:
+		var err error
+		if addr, err = tx.EncodeFilecoinAddress(1, hash); err != nil {
+			panic(err)
+		}
+	case multichain.Solana:
+		addr = base58.Encode(randomBytes(r, 32))
+	case multichain.Terra:
+		data, err := bech32.ConvertBits(hash, 8, 5, true)
+		if err != nil {
+			panic(err)
+		}
+		if addr, err = bech32.Encode("terra", data); err != nil {
+			panic(err)
+		}
+	default:
+		addr = "0x" + hex.EncodeToString(hash)
+	}
+	normalised, err := tx.NormaliseAddress(chain, addr)
+	if err != nil {
+		panic(err)
+	}
+	return normalised
+}
+
+// newLockMint returns a lock-and-mint transaction with coherent inputs.
+func newLockMint(r *rand.Rand, asset multichain.Asset, host multichain.Chain, amount pack.U256) (tx.Tx, error) {
+	selector := tx.Selector(fmt.Sprintf("%v/to%v", asset, host))
+	if !isSupportedSelector(selector) {
+		return tx.Tx{}, fmt.Errorf("unsupported selector %v", selector)
+	}
+	txid := randomBytes(r, 32)
+	txindex := uint32(r.Intn(4))
+	payload := randomBytes(r, r.Intn(64))
+	to := RandomAddress(r, host)
+	nonce := pack.NewBytes32(randomBytes32(r))
+	phash := tx.NewPHash(payload)
+	shash := tx.NewMintSelectorHash(asset, host)
+	return newTx(selector, pack.NewTyped(
+		"txid", pack.NewBytes(txid),
+		"txindex", pack.NewU32(txindex),
+		"amount", amount,
+		"payload", pack.NewBytes(payload),
+		"phash", phash,
+		"to", pack.String(to),
+		"nonce", nonce,
+		"nhash", tx.NewNHash(nonce, txid, txindex),
+		"gpubkey", pack.NewBytes(randomPubKey(r)),
+		"ghash", tx.NewGHash(phash, shash, rawAddress(host, to), nonce),
+	))
+}
+
+// newBurn returns a burn-and-release or burn-and-mint transaction with coherent
+// inputs. The txid is the hash of the burn transaction on the source chain,
+// and the nonce is the burn nonce. Burns are not sent to a gateway, so the
+// gpubkey is empty and the ghash is zero.
+func newBurn(r *rand.Rand, selector tx.Selector, amount pack.U256) (tx.Tx, error) {
+	if !isSupportedSelector(selector) {
+		return tx.Tx{}, fmt.Errorf("unsupported selector %v", selector)
+	}
+	txid := randomBytes(r, 32)
+	txindex := uint32(r.Intn(4))
+	nonce := pack.NewBytes32(pack.NewU256FromUint64(uint64(r.Int63())).Bytes32())
+	return newTx(selector, pack.NewTyped(
+		"txid", pack.NewBytes(txid),
+		"txindex", pack.NewU32(txindex),
+		"amount", amount,
+		"payload", pack.Bytes{},
+		"phash", tx.NewPHash(nil),
+		"to", pack.String(RandomAddress(r, selector.Destination())),
+		"nonce", nonce,
+		"nhash", tx.NewNHash(nonce, txid, txindex),
+		"gpubkey", pack.Bytes{},
+		"ghash", pack.Bytes32{},
+	))
+}
+
+func newTx(selector tx.Selector, input pack.Typed) (tx.Tx, error) {
+	transaction, err := tx.NewTx(selector, input)
+	if err != nil {
+		return tx.Tx{}, err
+	}
+	transaction.Output = pack.NewTyped()
+	return transaction, nil
+}
+
+func isSupportedSelector(selector tx.Selector) bool {
+	for _, supported := range AllSelectors() {
+		if selector == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// rawAddress returns the bytes of an address on a host chain, as they are
+// used in the ghash.
+func rawAddress(host multichain.Chain, addr string) []byte {
+	if host == multichain.Solana {
+		return base58.Decode(addr)
+	}
+	data, _ := hex.DecodeString(addr[2:])
+	return data
+}
+
+func randomPubKey(r *rand.Rand) []byte {
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), randomBytes(r, 32))
+	return key.PubKey().SerializeCompressed()
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	data := make([]byte, n)
+	r.Read(data)
+	return data
+}
+
+func randomBytes32(r *rand.Rand) [32]byte {
+	data := [32]byte{}
+	r.Read(data[:])
+	return data
+}
@@ -0,0 +1,258 @@
+package txutil_test
+
+import (
+	"encoding/hex"
+	"math/rand"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scenarios", func() {
+
+	// expectCoherent checks that the hashes of a cross-chain transaction are
+	// derived from its other inputs.
+	expectCoherent := func(transaction tx.Tx) {
+		hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transaction.Hash).To(Equal(hash))
+		Expect(txutil.AllSelectors()).To(ContainElement(transaction.Selector))
+		Expect(transaction.ValidateRecipient()).To(Succeed())
+
+		payload := transaction.Input.Get("payload").(pack.Bytes)
+		nonce := transaction.Input.Get("nonce").(pack.Bytes32)
+		txid := transaction.Input.Get("txid").(pack.Bytes)
+		txindex := transaction.Input.Get("txindex").(pack.U32)
+		Expect(transaction.Input.Get("phash")).To(Equal(tx.NewPHash(payload)))
+		Expect(transaction.Input.Get("nhash")).To(Equal(tx.NewNHash(nonce, txid, txindex.Uint32())))
+	}
+
+	Context("when building a lock-mint", func() {
+		It("should derive the phash, nhash, and ghash", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for _, asset := range txutil.SupportedAssets() {
+				for _, host := range txutil.SupportedHostChains() {
+					scenario, err := txutil.NewLockMintScenario(r, asset, host)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(scenario.Steps).To(HaveLen(1))
+					Expect(scenario.Steps[0].Statuses).To(Equal(txutil.CrossChainStatuses()))
+
+					mint := scenario.Steps[0].Tx
+					Expect(mint.Selector.IsLock() && mint.Selector.IsMint()).To(BeTrue())
+					expectCoherent(mint)
+					Expect(mint.Input.Get("ghash")).ToNot(Equal(pack.Bytes32{}))
+					Expect(mint.Input.Get("gpubkey").(pack.Bytes)).To(HaveLen(33))
+				}
+			}
+		})
+
+		It("should compute the ghash from the raw recipient", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewLockMintScenario(r, multichain.BTC, multichain.Ethereum)
+			Expect(err).ToNot(HaveOccurred())
+			mint := scenario.Steps[0].Tx
+			to, err := tx.NormaliseAddress(multichain.Ethereum, string(mint.Input.Get("to").(pack.String)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(to).To(HavePrefix("0x"))
+
+			raw, err := hex.DecodeString(to[2:])
+			Expect(err).ToNot(HaveOccurred())
+			phash := mint.Input.Get("phash").(pack.Bytes32)
+			nonce := mint.Input.Get("nonce").(pack.Bytes32)
+			shash := tx.NewMintSelectorHash(multichain.BTC, multichain.Ethereum)
+			Expect(mint.Input.Get("ghash")).To(Equal(tx.NewGHash(phash, shash, raw, nonce)))
+		})
+
+		It("should return an error for unsupported routes", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			_, err := txutil.NewLockMintScenario(r, multichain.BTC, multichain.Bitcoin)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when building a round trip", func() {
+		It("should burn and release the minted amount", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewRoundTripScenario(r, multichain.BTC, multichain.Ethereum, tx.FeeSchedule{Asset: multichain.BTC})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scenario.Steps).To(HaveLen(2))
+
+			mint, release := scenario.Steps[0].Tx, scenario.Steps[1].Tx
+			Expect(release.Selector).To(Equal(tx.Selector("BTC/fromEthereum")))
+			Expect(release.Selector.Destination()).To(Equal(multichain.Bitcoin))
+			Expect(release.Input.Get("amount")).To(Equal(mint.Input.Get("amount")))
+			expectCoherent(mint)
+			expectCoherent(release)
+			Expect(scenario.Steps[1].Statuses).To(Equal(txutil.CrossChainStatuses()))
+		})
+
+		It("should burn the amount that remains after the mint fees", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			fees := tx.FeeSchedule{
+				Asset: multichain.BTC,
+				Chains: []tx.ChainFees{
+					{Chain: multichain.Bitcoin, UnderlyingFee: pack.NewU256FromUint64(1000)},
+					{Chain: multichain.Ethereum, UnderlyingFee: pack.NewU256FromUint64(2000), MintFee: 15, BurnFee: 15},
+				},
+			}
+			scenario, err := txutil.NewRoundTripScenario(r, multichain.BTC, multichain.Ethereum, fees)
+			Expect(err).ToNot(HaveOccurred())
+
+			mint, release := scenario.Steps[0].Tx, scenario.Steps[1].Tx
+			locked, err := mint.Amount()
+			Expect(err).ToNot(HaveOccurred())
+			burned, err := release.Amount()
+			Expect(err).ToNot(HaveOccurred())
+
+			// The locked amount is reduced by the underlying fee of Bitcoin,
+			// and then by 0.15%, rounded down.
+			lockedValue := locked.Value.Int().Uint64()
+			Expect(burned.Value.Int().Uint64()).To(Equal(lockedValue - 1000 - (lockedValue-1000)*15/10000))
+			expectCoherent(release)
+		})
+
+		It("should return an error when the fees are greater than the amount", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			fees := tx.FeeSchedule{Asset: multichain.BTC, Chains: []tx.ChainFees{{Chain: multichain.Bitcoin, UnderlyingFee: pack.MaxU256}}}
+			_, err := txutil.NewRoundTripScenario(r, multichain.BTC, multichain.Ethereum, fees)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when building a burn-mint", func() {
+		It("should burn from one host and mint to another", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewBurnMintScenario(r, multichain.LUNA, multichain.Ethereum, multichain.Solana, tx.FeeSchedule{Asset: multichain.LUNA})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scenario.Steps).To(HaveLen(2))
+
+			mint, burnMint := scenario.Steps[0].Tx, scenario.Steps[1].Tx
+			Expect(mint.Selector.Destination()).To(Equal(multichain.Ethereum))
+			Expect(burnMint.Selector.Source()).To(Equal(multichain.Ethereum))
+			Expect(burnMint.Selector.Destination()).To(Equal(multichain.Solana))
+			Expect(burnMint.Input.Get("amount")).To(Equal(mint.Input.Get("amount")))
+			expectCoherent(mint)
+			expectCoherent(burnMint)
+		})
+
+		It("should burn the amount that remains after the mint fees", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			fees := tx.FeeSchedule{
+				Asset: multichain.LUNA,
+				Chains: []tx.ChainFees{
+					{Chain: multichain.Terra, UnderlyingFee: pack.NewU256FromUint64(1000)},
+					{Chain: multichain.Ethereum, UnderlyingFee: pack.NewU256FromUint64(2000), MintFee: 15, BurnFee: 15},
+				},
+			}
+			scenario, err := txutil.NewBurnMintScenario(r, multichain.LUNA, multichain.Ethereum, multichain.Solana, fees)
+			Expect(err).ToNot(HaveOccurred())
+
+			mint, burnMint := scenario.Steps[0].Tx, scenario.Steps[1].Tx
+			locked, err := mint.Amount()
+			Expect(err).ToNot(HaveOccurred())
+			burned, err := burnMint.Amount()
+			Expect(err).ToNot(HaveOccurred())
+
+			// The locked amount is reduced by the underlying fee of Terra, and
+			// then by 0.15%, rounded down.
+			lockedValue := locked.Value.Int().Uint64()
+			Expect(burned.Value.Int().Uint64()).To(Equal(lockedValue - 1000 - (lockedValue-1000)*15/10000))
+			expectCoherent(burnMint)
+		})
+
+		It("should return an error when the fees are greater than the amount", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			fees := tx.FeeSchedule{Asset: multichain.LUNA, Chains: []tx.ChainFees{{Chain: multichain.Terra, UnderlyingFee: pack.MaxU256}}}
+			_, err := txutil.NewBurnMintScenario(r, multichain.LUNA, multichain.Ethereum, multichain.Solana, fees)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error when the hosts are the same", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			_, err := txutil.NewBurnMintScenario(r, multichain.BTC, multichain.Ethereum, multichain.Ethereum, tx.FeeSchedule{Asset: multichain.BTC})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when building a duplicate deposit", func() {
+		It("should reject a second transaction with the same nhash", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewDuplicateDepositScenario(r, multichain.DOGE, multichain.Polygon)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scenario.Steps).To(HaveLen(2))
+
+			mint, duplicate := scenario.Steps[0].Tx, scenario.Steps[1].Tx
+			expectCoherent(mint)
+			expectCoherent(duplicate)
+			Expect(duplicate.Hash).ToNot(Equal(mint.Hash))
+			Expect(duplicate.Input.Get("nhash")).To(Equal(mint.Input.Get("nhash")))
+			Expect(scenario.Steps[0].Statuses).To(Equal(txutil.CrossChainStatuses()))
+			Expect(scenario.Steps[1].Statuses).To(Equal([]tx.Status{tx.StatusNil}))
+		})
+	})
+
+	Context("when building an epoch", func() {
+		It("should return an epoch intrinsic", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewEpochScenario(r, "BTC", 7)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scenario.Steps).To(HaveLen(1))
+			Expect(scenario.Steps[0].Statuses).To(Equal([]tx.Status{tx.StatusExecuting, tx.StatusDone}))
+
+			epoch := scenario.Steps[0].Tx
+			Expect(epoch.Selector.IsIntrinsic()).To(BeTrue())
+			input, err := epoch.IntrinsicInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input.(tx.EpochInput).Number).To(Equal(pack.NewU64(7)))
+		})
+	})
+
+	Context("when building random scenarios", func() {
+		It("should build transactions with valid hashes and recipients", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				scenario := txutil.RandomScenario(r)
+				Expect(scenario.Steps).ToNot(BeEmpty())
+				for _, step := range scenario.Steps {
+					Expect(step.Statuses).ToNot(BeEmpty())
+					if step.Tx.Selector.IsIntrinsic() {
+						continue
+					}
+					expectCoherent(step.Tx)
+				}
+			}
+		})
+
+		It("should pair each transaction with its statuses", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			scenario, err := txutil.NewRoundTripScenario(r, multichain.ZEC, multichain.Avalanche, tx.FeeSchedule{Asset: multichain.ZEC})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scenario.Txs()).To(Equal([]tx.Tx{scenario.Steps[0].Tx, scenario.Steps[1].Tx}))
+			txs := scenario.Steps[0].WithStatuses()
+			Expect(txs).To(HaveLen(4))
+			for i, transaction := range txs {
+				Expect(transaction.Tx).To(Equal(scenario.Steps[0].Tx))
+				Expect(transaction.Status).To(Equal(txutil.CrossChainStatuses()[i]))
+			}
+		})
+	})
+
+	Context("when generating addresses", func() {
+		It("should generate valid addresses for every supported chain", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			chains := txutil.SupportedHostChains()
+			for _, asset := range txutil.SupportedAssets() {
+				chains = append(chains, asset.OriginChain())
+			}
+			for _, chain := range chains {
+				addr := txutil.RandomAddress(r, chain)
+				Expect(tx.ValidateAddress(chain, addr)).To(Succeed())
+			}
+		})
+	})
+})
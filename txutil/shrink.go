@@ -0,0 +1,408 @@
+package txutil
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing/quick"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+)
+
+// MaxShrinkSteps is the maximum number of times that a failing value will be
+// shrunk. Shrinking always terminates, but this bounds the time spent on very
+// large values.
+const MaxShrinkSteps = 1000
+
+// A Shrinkable value can be generated by testing/quick, and shrunk into
+// smaller candidates when a property fails for it.
+type Shrinkable interface {
+	quick.Generator
+
+	// Shrink returns candidates that are strictly smaller than the value,
+	// most aggressive first. It returns nothing if the value is minimal.
+	Shrink() []Shrinkable
+}
+
+// Shrink a value that fails a property into a smaller value that also fails
+// it. At each step, the first candidate that still fails is kept, until no
+// candidate fails. It returns the smallest failing value that was found, and
+// the number of steps taken.
+func Shrink(v Shrinkable, fails func(Shrinkable) bool) (Shrinkable, int) {
+	steps := 0
+	for steps < MaxShrinkSteps {
+		shrunk := false
+		for _, candidate := range v.Shrink() {
+			if fails(candidate) {
+				v = candidate
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+		steps++
+	}
+	return v, steps
+}
+
+// A ShrinkError is returned by CheckShrink when a property fails. It contains
+// the original failing inputs, and the shrunk inputs.
+type ShrinkError struct {
+	*quick.CheckError
+	// Shrunk are the arguments after shrinking.
+	Shrunk []interface{}
+	// Steps is the total number of shrinking steps.
+	Steps int
+}
+
+func (err *ShrinkError) Error() string {
+	return fmt.Sprintf("#%d: failed on input %v (shrunk in %d steps from %v)", err.Count, err.Shrunk, err.Steps, err.In)
+}
+
+// CheckShrink is the same as quick.Check, but when the property fails, every
+// argument that is Shrinkable is shrunk in turn. A *ShrinkError is returned
+// when the property fails. A panic in the property is treated as a failure.
+//
+//	err := txutil.CheckShrink(func(transaction txutil.GoodTx) bool {
+//		return pool.Add(transaction.Tx) == nil
+//	}, nil)
+func CheckShrink(f interface{}, config *quick.Config) error {
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func || fv.Type().NumOut() != 1 || fv.Type().Out(0).Kind() != reflect.Bool {
+		return quick.SetupError("function does not return one bool")
+	}
+	property := reflect.MakeFunc(fv.Type(), func(in []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(callProperty(fv, in)).Convert(fv.Type().Out(0))}
+	})
+	err := quick.Check(property.Interface(), config)
+	checkErr, ok := err.(*quick.CheckError)
+	if !ok {
+		return err
+	}
+
+	args := make([]interface{}, len(checkErr.In))
+	copy(args, checkErr.In)
+	steps := 0
+	for i := range args {
+		arg, ok := args[i].(Shrinkable)
+		if !ok {
+			continue
+		}
+		shrunk, n := Shrink(arg, func(candidate Shrinkable) bool {
+			in := make([]interface{}, len(args))
+			copy(in, args)
+			in[i] = candidate
+			values := make([]reflect.Value, len(in))
+			for j := range in {
+				values[j] = reflect.ValueOf(in[j])
+			}
+			return !callProperty(fv, values)
+		})
+		args[i] = shrunk
+		steps += n
+	}
+	return &ShrinkError{CheckError: checkErr, Shrunk: args, Steps: steps}
+}
+
+// callProperty calls a property with arguments, and returns its result. A
+// panic is treated as a failure.
+func callProperty(f reflect.Value, in []reflect.Value) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	return f.Call(in)[0].Bool()
+}
+
+// GoodTx is a good transaction (see RandomGoodTx) that can be generated and
+// shrunk. Shrinking keeps it good: the selector remains one of AllSelectors,
+// and the hash is recomputed. It does not keep it valid for tx.Tx.Validate,
+// because inputs can be removed or shrunk.
+type GoodTx struct {
+	tx.Tx
+}
+
+// Generate a random good transaction using RandomGoodTx.
+func (GoodTx) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(GoodTx{RandomGoodTx(r)})
+}
+
+// Shrink the transaction using ShrinkGoodTx.
+func (transaction GoodTx) Shrink() []Shrinkable {
+	txs := ShrinkGoodTx(transaction.Tx)
+	candidates := make([]Shrinkable, len(txs))
+	for i := range txs {
+		candidates[i] = GoodTx{txs[i]}
+	}
+	return candidates
+}
+
+func (transaction GoodTx) String() string {
+	return formatTx(transaction.Tx)
+}
+
+// BadTx is an invalid transaction that can be generated and shrunk.
+type BadTx struct {
+	tx.Tx
+}
+
+// Generate a random bad transaction using RandomBadTx.
+func (BadTx) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(BadTx{RandomBadTx(r)})
+}
+
+// Shrink the transaction using ShrinkBadTx.
+func (transaction BadTx) Shrink() []Shrinkable {
+	txs := ShrinkBadTx(transaction.Tx)
+	candidates := make([]Shrinkable, len(txs))
+	for i := range txs {
+		candidates[i] = BadTx{txs[i]}
+	}
+	return candidates
+}
+
+func (transaction BadTx) String() string {
+	return formatTx(transaction.Tx)
+}
+
+// ShrinkGoodTx returns smaller good transactions: with a simpler selector, or
+// with a smaller input (see ShrinkInput). The candidates have correct hashes,
+// but they are not necessarily valid for tx.Tx.Validate. Selectors are ordered
+// by length, and then alphabetically, so a transaction shrinks towards the
+// shortest selector.
+func ShrinkGoodTx(transaction tx.Tx) []tx.Tx {
+	candidates := []tx.Tx{}
+	add := func(selector tx.Selector, input pack.Typed) {
+		shrunk, err := tx.NewTx(selector, input)
+		if err != nil {
+			return
+		}
+		shrunk.Version = transaction.Version
+		if shrunk.Hash, err = tx.NewTxHash(shrunk.Version, shrunk.Selector, shrunk.Input); err != nil {
+			return
+		}
+		candidates = append(candidates, shrunk)
+	}
+	for _, selector := range shrinkSelector(transaction.Selector) {
+		add(selector, transaction.Input)
+	}
+	for _, input := range ShrinkInput(transaction.Input) {
+		add(transaction.Selector, input)
+	}
+	return candidates
+}
+
+// ShrinkBadTx returns smaller transactions: with an empty hash, a shorter
+// selector, or a smaller input (see ShrinkInput). The hash is not recomputed.
+func ShrinkBadTx(transaction tx.Tx) []tx.Tx {
+	candidates := []tx.Tx{}
+	if transaction.Hash != (tx.Tx{}).Hash {
+		shrunk := transaction
+		shrunk.Hash = (tx.Tx{}).Hash
+		candidates = append(candidates, shrunk)
+	}
+	for _, selector := range shrinkString(string(transaction.Selector)) {
+		shrunk := transaction
+		shrunk.Selector = tx.Selector(selector)
+		candidates = append(candidates, shrunk)
+	}
+	for _, input := range ShrinkInput(transaction.Input) {
+		shrunk := transaction
+		shrunk.Input = input
+		candidates = append(candidates, shrunk)
+	}
+	return candidates
+}
+
+// ShrinkInput returns smaller inputs: with a field removed, or with the value
+// of a field shrunk. Numbers shrink towards zero (by repeatedly halving the
+// distance to the value), strings and bytes shrink towards empty, fixed-size
+// bytes shrink to zero, structs shrink recursively, and lists shrink by
+// removing elements, and then by shrinking their elements.
+func ShrinkInput(input pack.Typed) []pack.Typed {
+	candidates := []pack.Typed{}
+	for _, s := range shrinkStruct(pack.Struct(input)) {
+		candidates = append(candidates, pack.Typed(s))
+	}
+	return candidates
+}
+
+func shrinkStruct(s pack.Struct) []pack.Struct {
+	candidates := []pack.Struct{}
+	// Removing a field is the most aggressive shrink, so it comes first.
+	for i := range s {
+		shrunk := make(pack.Struct, 0, len(s)-1)
+		shrunk = append(shrunk, s[:i]...)
+		shrunk = append(shrunk, s[i+1:]...)
+		candidates = append(candidates, shrunk)
+	}
+	for i := range s {
+		for _, value := range shrinkValue(s[i].Value) {
+			shrunk := make(pack.Struct, len(s))
+			copy(shrunk, s)
+			shrunk[i] = pack.NewStructField(s[i].Name, value)
+			candidates = append(candidates, shrunk)
+		}
+	}
+	return candidates
+}
+
+func shrinkValue(value pack.Value) []pack.Value {
+	candidates := []pack.Value{}
+	switch v := value.(type) {
+	case pack.Bool:
+		if v {
+			candidates = append(candidates, pack.NewBool(false))
+		}
+	case pack.U8:
+		for _, x := range shrinkUint(uint64(v.Uint8())) {
+			candidates = append(candidates, pack.NewU8(uint8(x)))
+		}
+	case pack.U16:
+		for _, x := range shrinkUint(uint64(v.Uint16())) {
+			candidates = append(candidates, pack.NewU16(uint16(x)))
+		}
+	case pack.U32:
+		for _, x := range shrinkUint(uint64(v.Uint32())) {
+			candidates = append(candidates, pack.NewU32(uint32(x)))
+		}
+	case pack.U64:
+		for _, x := range shrinkUint(v.Uint64()) {
+			candidates = append(candidates, pack.NewU64(x))
+		}
+	case pack.U128:
+		for _, x := range shrinkBigUint(v.Int()) {
+			candidates = append(candidates, pack.NewU128FromInt(x))
+		}
+	case pack.U256:
+		if v != (pack.U256{}) {
+			for _, x := range shrinkBigUint(v.Int()) {
+				candidates = append(candidates, pack.NewU256FromInt(x))
+			}
+		}
+	case pack.String:
+		for _, x := range shrinkString(string(v)) {
+			candidates = append(candidates, pack.String(x))
+		}
+	case pack.Bytes:
+		if len(v) > 0 {
+			candidates = append(candidates, pack.Bytes{}, v[:len(v)/2], v[:len(v)-1])
+		}
+	case pack.Bytes32:
+		if v != (pack.Bytes32{}) {
+			candidates = append(candidates, pack.Bytes32{})
+		}
+	case pack.Bytes65:
+		if v != (pack.Bytes65{}) {
+			candidates = append(candidates, pack.Bytes65{})
+		}
+	case pack.Struct:
+		for _, s := range shrinkStruct(v) {
+			candidates = append(candidates, s)
+		}
+	case pack.List:
+		for _, l := range shrinkList(v) {
+			candidates = append(candidates, l)
+		}
+	}
+	return candidates
+}
+
+// shrinkList returns the empty list, the list with each element removed, and
+// then the list with each element shrunk. The type of the list is unchanged.
+func shrinkList(l pack.List) []pack.List {
+	if len(l.Elems) == 0 {
+		return nil
+	}
+	candidates := []pack.List{{T: l.T, Elems: []pack.Value{}}}
+	if len(l.Elems) > 1 {
+		for i := range l.Elems {
+			elems := make([]pack.Value, 0, len(l.Elems)-1)
+			elems = append(elems, l.Elems[:i]...)
+			elems = append(elems, l.Elems[i+1:]...)
+			candidates = append(candidates, pack.List{T: l.T, Elems: elems})
+		}
+	}
+	for i := range l.Elems {
+		for _, elem := range shrinkValue(l.Elems[i]) {
+			elems := make([]pack.Value, len(l.Elems))
+			copy(elems, l.Elems)
+			elems[i] = elem
+			candidates = append(candidates, pack.List{T: l.T, Elems: elems})
+		}
+	}
+	return candidates
+}
+
+// shrinkUint returns zero, and then values that are closer and closer to x,
+// so that the shrunk value converges on a boundary in logarithmic time.
+func shrinkUint(x uint64) []uint64 {
+	if x == 0 {
+		return nil
+	}
+	candidates := []uint64{0}
+	for d := x / 2; d > 0; d /= 2 {
+		candidates = append(candidates, x-d)
+	}
+	return candidates
+}
+
+func shrinkBigUint(x *big.Int) []*big.Int {
+	if x.Sign() == 0 {
+		return nil
+	}
+	candidates := []*big.Int{new(big.Int)}
+	for d := new(big.Int).Rsh(x, 1); d.Sign() > 0; d = new(big.Int).Rsh(d, 1) {
+		candidates = append(candidates, new(big.Int).Sub(x, d))
+	}
+	return candidates
+}
+
+func shrinkString(str string) []string {
+	if str == "" {
+		return nil
+	}
+	runes := []rune(str)
+	if len(runes) == 1 {
+		return []string{""}
+	}
+	return []string{"", string(runes[:len(runes)/2]), string(runes[:len(runes)-1])}
+}
+
+// shrinkSelector returns the valid selectors that come before the selector,
+// when ordered by length and then alphabetically: the first one, and the one
+// half way to the selector.
+func shrinkSelector(selector tx.Selector) []tx.Selector {
+	selectors := AllSelectors()
+	sort.Slice(selectors, func(i, j int) bool {
+		return lessSelector(selectors[i], selectors[j])
+	})
+	n := sort.Search(len(selectors), func(i int) bool {
+		return !lessSelector(selectors[i], selector)
+	})
+	switch {
+	case n == 0:
+		return nil
+	case n == 1:
+		return selectors[:1]
+	default:
+		return []tx.Selector{selectors[0], selectors[n/2]}
+	}
+}
+
+func lessSelector(a, b tx.Selector) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func formatTx(transaction tx.Tx) string {
+	return fmt.Sprintf("Tx{hash=%v version=%q selector=%q input=%v}", transaction.Hash, transaction.Version, transaction.Selector, transaction.Input)
+}
@@ -0,0 +1,174 @@
+package txutil_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing/quick"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shrinking", func() {
+
+	// minSelector is the selector that good transactions shrink towards.
+	minSelector := func() tx.Selector {
+		selectors := txutil.AllSelectors()
+		sort.Slice(selectors, func(i, j int) bool {
+			if len(selectors[i]) != len(selectors[j]) {
+				return len(selectors[i]) < len(selectors[j])
+			}
+			return selectors[i] < selectors[j]
+		})
+		return selectors[0]
+	}
+
+	Context("when generating good transactions", func() {
+		It("should be compatible with testing/quick", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			v, ok := quick.Value(reflect.TypeOf(txutil.GoodTx{}), r)
+			Expect(ok).To(BeTrue())
+			transaction := v.Interface().(txutil.GoodTx)
+			hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Hash).To(Equal(hash))
+		})
+
+		It("should shrink into good transactions", func() {
+			selectors := txutil.AllSelectors()
+			f := func(transaction txutil.GoodTx) bool {
+				for _, candidate := range transaction.Shrink() {
+					shrunk := candidate.(txutil.GoodTx)
+					hash, err := tx.NewTxHash(shrunk.Version, shrunk.Selector, shrunk.Input)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(shrunk.Hash).To(Equal(hash))
+					Expect(selectors).To(ContainElement(shrunk.Selector))
+				}
+				return true
+			}
+			Expect(quick.Check(f, nil)).To(Succeed())
+		})
+	})
+
+	Context("when a property fails for a good transaction", func() {
+		It("should shrink to a minimal selector and input", func() {
+			threshold := pack.NewU256FromUint64(1000)
+			f := func(transaction txutil.GoodTx) bool {
+				amount, ok := transaction.Input.Get("amount").(pack.U256)
+				return !ok || amount.LessThan(threshold)
+			}
+			err := txutil.CheckShrink(f, nil)
+			Expect(err).To(HaveOccurred())
+			shrinkErr, ok := err.(*txutil.ShrinkError)
+			Expect(ok).To(BeTrue())
+			Expect(shrinkErr.Steps).To(BeNumerically(">", 0))
+
+			shrunk := shrinkErr.Shrunk[0].(txutil.GoodTx)
+			Expect(shrunk.Selector).To(Equal(minSelector()))
+			Expect(shrunk.Input).To(Equal(pack.NewTyped("amount", threshold)))
+			Expect(f(shrunk)).To(BeFalse())
+			Expect(err.Error()).To(ContainSubstring(string(minSelector())))
+		})
+
+		It("should treat panics as failures", func() {
+			f := func(transaction txutil.GoodTx) bool {
+				if transaction.Input.Get("txid") != nil {
+					panic("txid")
+				}
+				return true
+			}
+			err := txutil.CheckShrink(f, nil)
+			Expect(err).To(HaveOccurred())
+			shrunk := err.(*txutil.ShrinkError).Shrunk[0].(txutil.GoodTx)
+			Expect(shrunk.Input).To(Equal(pack.NewTyped("txid", pack.Bytes{})))
+		})
+	})
+
+	Context("when a property fails for a bad transaction", func() {
+		It("should shrink to an empty transaction", func() {
+			err := txutil.CheckShrink(func(transaction txutil.BadTx, n uint8) bool {
+				return false
+			}, nil)
+			shrunk := err.(*txutil.ShrinkError).Shrunk
+			Expect(shrunk[0].(txutil.BadTx).Tx).To(Equal(tx.Tx{}))
+		})
+	})
+
+	Context("when a property holds", func() {
+		It("should return nil", func() {
+			Expect(txutil.CheckShrink(func(transaction txutil.GoodTx) bool {
+				return true
+			}, nil)).To(Succeed())
+		})
+	})
+
+	Context("when shrinking inputs", func() {
+		It("should terminate at the empty input", func() {
+			input := pack.NewTyped(
+				"bool", pack.NewBool(true),
+				"u64", pack.NewU64(10),
+				"string", pack.String("abc"),
+				"bytes32", pack.Bytes32{1},
+				"struct", pack.NewStruct("u8", pack.NewU8(1)),
+				"list", pack.List{T: pack.U64(0).Type(), Elems: []pack.Value{pack.NewU64(1), pack.NewU64(2)}},
+			)
+			for len(txutil.ShrinkInput(input)) > 0 {
+				candidates := txutil.ShrinkInput(input)
+				// Skip field removal, so that values are shrunk.
+				input = candidates[len(candidates)-1]
+			}
+			Expect(input).To(Equal(pack.NewTyped()))
+		})
+
+		It("should remove and shrink the elements of lists", func() {
+			list := pack.List{T: pack.U64(0).Type(), Elems: []pack.Value{pack.NewU64(1), pack.NewU64(2000), pack.NewU64(3)}}
+			fails := func(v txutil.Shrinkable) bool {
+				// Fail when the list has an element that is at least 1000.
+				for _, elem := range v.(shrinkableInput).Input.Get("list").(pack.List).Elems {
+					if elem.(pack.U64).Uint64() >= 1000 {
+						return true
+					}
+				}
+				return false
+			}
+			shrunk, _ := txutil.Shrink(shrinkableInput{pack.NewTyped("list", list)}, fails)
+			Expect(shrunk.(shrinkableInput).Input).To(Equal(pack.NewTyped(
+				"list", pack.List{T: list.T, Elems: []pack.Value{pack.NewU64(1000)}},
+			)))
+		})
+
+		It("should keep the type of lists", func() {
+			list := pack.List{T: pack.Bytes{}.Type(), Elems: []pack.Value{pack.NewBytes([]byte{1})}}
+			for _, candidate := range txutil.ShrinkInput(pack.NewTyped("list", list)) {
+				if shrunk, ok := candidate.Get("list").(pack.List); ok {
+					Expect(shrunk.T).To(Equal(list.T))
+				}
+			}
+		})
+	})
+})
+
+// shrinkableInput shrinks an input using ShrinkInput, without removing the
+// fields that are used by the property.
+type shrinkableInput struct {
+	Input pack.Typed
+}
+
+func (shrinkableInput) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(shrinkableInput{pack.NewTyped()})
+}
+
+func (v shrinkableInput) Shrink() []txutil.Shrinkable {
+	candidates := []txutil.Shrinkable{}
+	for _, input := range txutil.ShrinkInput(v.Input) {
+		if input.Get("list") != nil {
+			candidates = append(candidates, shrinkableInput{input})
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,235 @@
+package txutil
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+)
+
+// A Distribution returns random samples. Streams use distributions for payload
+// sizes (in bytes) and for the intervals between transactions (in seconds).
+type Distribution func(r *rand.Rand) float64
+
+// ConstantDistribution always returns the same value.
+func ConstantDistribution(value float64) Distribution {
+	return func(*rand.Rand) float64 {
+		return value
+	}
+}
+
+// UniformDistribution returns values that are uniformly distributed in the
+// interval [min, max).
+func UniformDistribution(min, max float64) Distribution {
+	return func(r *rand.Rand) float64 {
+		return min + r.Float64()*(max-min)
+	}
+}
+
+// ExponentialDistribution returns values that are exponentially distributed
+// with the given mean.
+func ExponentialDistribution(mean float64) Distribution {
+	return func(r *rand.Rand) float64 {
+		return r.ExpFloat64() * mean
+	}
+}
+
+// PoissonArrivals returns the distribution of intervals between transactions
+// that arrive at random, at an average rate per second.
+func PoissonArrivals(rate float64) Distribution {
+	return ExponentialDistribution(1 / rate)
+}
+
+// A RouteKind is a kind of cross-chain route.
+type RouteKind string
+
+// Enumeration of all route kinds.
+const (
+	RouteLockMint    = RouteKind("lock-mint")
+	RouteBurnRelease = RouteKind("burn-release")
+	RouteBurnMint    = RouteKind("burn-mint")
+)
+
+// KindOfSelector returns the kind of route of a cross-chain selector.
+func KindOfSelector(selector tx.Selector) RouteKind {
+	switch {
+	case selector.IsLock():
+		return RouteLockMint
+	case selector.IsRelease():
+		return RouteBurnRelease
+	default:
+		return RouteBurnMint
+	}
+}
+
+// StreamOptions configure a Stream. The zero value is a stream that emits
+// transactions for all selectors with equal probability, with no payload, as
+// fast as they can be received.
+type StreamOptions struct {
+	// Seed of the stream. Streams with the same seed and options emit the same
+	// transactions, in the same order, at the same intervals.
+	Seed int64
+	// Selectors that transactions are sent to. If empty, AllSelectors is used.
+	Selectors []tx.Selector
+	// AssetWeights are the relative weights of assets. Assets that are not in
+	// the map have weight 1, and assets with weight 0 are never used.
+	AssetWeights map[multichain.Asset]float64
+	// RouteWeights are the relative weights of route kinds. Kinds that are not
+	// in the map have weight 1, and kinds with weight 0 are never used. The
+	// weight of a selector is the weight of its asset multiplied by the weight
+	// of its route kind.
+	RouteWeights map[RouteKind]float64
+	// PayloadSize is the distribution of payload sizes, in bytes. Samples are
+	// rounded down, and are clamped to at most tx.MaxPayloadBytes. Negative
+	// and non-finite samples are treated as zero. If nil, payloads are empty.
+	PayloadSize Distribution
+	// Interval is the distribution of intervals between transactions, in
+	// seconds. If nil, transactions are emitted as fast as they are received.
+	Interval Distribution
+	// Count is the number of transactions that are emitted. If zero, the
+	// stream does not stop until its context is done.
+	Count int
+}
+
+// A Stream is a mock source of transactions, for load and soak testing. It
+// emits good transactions (see RandomGoodTx) with selectors, payload sizes, and
+// arrival times that follow the distributions in its options. A Stream is not
+// safe for concurrent use.
+type Stream struct {
+	opts StreamOptions
+
+	// txR generates transactions, and intervalR generates intervals, so that
+	// the transactions of a stream do not depend on whether it is run.
+	txR       *rand.Rand
+	intervalR *rand.Rand
+
+	selectors  []tx.Selector
+	cumWeights []float64
+}
+
+// NewStream returns a stream with the given options. An error is returned if a
+// weight is negative, or if every selector has weight zero.
+func NewStream(opts StreamOptions) (*Stream, error) {
+	selectors := opts.Selectors
+	if len(selectors) == 0 {
+		selectors = AllSelectors()
+	}
+	for asset, w := range opts.AssetWeights {
+		if !validWeight(w) {
+			return nil, fmt.Errorf("invalid weight %v for asset %v", w, asset)
+		}
+	}
+	for kind, w := range opts.RouteWeights {
+		if !validWeight(w) {
+			return nil, fmt.Errorf("invalid weight %v for route kind %v", w, kind)
+		}
+	}
+
+	stream := &Stream{
+		opts:      opts,
+		txR:       rand.New(rand.NewSource(opts.Seed)),
+		intervalR: rand.New(rand.NewSource(opts.Seed + 1)),
+	}
+	total := 0.0
+	for _, selector := range selectors {
+		assetWeight, ok := opts.AssetWeights[selector.Asset()]
+		if !ok {
+			assetWeight = 1
+		}
+		routeWeight, ok := opts.RouteWeights[KindOfSelector(selector)]
+		if !ok {
+			routeWeight = 1
+		}
+		if w := assetWeight * routeWeight; w > 0 {
+			total += w
+			stream.selectors = append(stream.selectors, selector)
+			stream.cumWeights = append(stream.cumWeights, total)
+		}
+	}
+	if len(stream.selectors) == 0 {
+		return nil, fmt.Errorf("every selector has weight zero")
+	}
+	return stream, nil
+}
+
+func validWeight(w float64) bool {
+	return w >= 0 && !math.IsInf(w, 0)
+}
+
+// Next returns the next transaction of the stream, without waiting.
+func (stream *Stream) Next() tx.Tx {
+	r := stream.txR
+	total := stream.cumWeights[len(stream.cumWeights)-1]
+	i := sort.SearchFloat64s(stream.cumWeights, r.Float64()*total)
+	if i == len(stream.cumWeights) {
+		// Rounding can make the sample equal to the total.
+		i--
+	}
+	selector := stream.selectors[i]
+
+	input := RandomTxInput(r)
+	if stream.opts.PayloadSize != nil {
+		size := stream.opts.PayloadSize(r)
+		if size < 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+			size = 0
+		}
+		if size > tx.MaxPayloadBytes {
+			size = tx.MaxPayloadBytes
+		}
+		payload := make([]byte, int(size))
+		r.Read(payload)
+		input = setInput(input, "payload", pack.NewBytes(payload))
+		input = setInput(input, "phash", tx.NewPHash(payload))
+	} else {
+		input = setInput(input, "payload", pack.Bytes{})
+		input = setInput(input, "phash", tx.NewPHash(nil))
+	}
+	return NewGoodTx(selector, input)
+}
+
+// Run emits transactions on the returned channel until the context is done, or
+// until Count transactions have been emitted, and then closes the channel.
+// Transactions are scheduled from the start of the stream, so when a receiver
+// falls behind the schedule, transactions are emitted without waiting until
+// the stream has caught up. Run must not be called more than once.
+func (stream *Stream) Run(ctx context.Context) <-chan tx.Tx {
+	txs := make(chan tx.Tx)
+	go func() {
+		defer close(txs)
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		<-timer.C
+
+		due := time.Now()
+		for n := 0; stream.opts.Count == 0 || n < stream.opts.Count; n++ {
+			if stream.opts.Interval != nil {
+				interval := stream.opts.Interval(stream.intervalR)
+				if interval > 0 && !math.IsInf(interval, 0) {
+					due = due.Add(time.Duration(interval * float64(time.Second)))
+				}
+				if wait := time.Until(due); wait > 0 {
+					timer.Reset(wait)
+					select {
+					case <-ctx.Done():
+						return
+					case <-timer.C:
+					}
+				}
+			}
+			transaction := stream.Next()
+			select {
+			case <-ctx.Done():
+				return
+			case txs <- transaction:
+			}
+		}
+	}()
+	return txs
+}
@@ -0,0 +1,176 @@
+package txutil_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Streams", func() {
+
+	// collect returns the next n transactions of a stream.
+	collect := func(stream *txutil.Stream, n int) []tx.Tx {
+		txs := make([]tx.Tx, n)
+		for i := range txs {
+			txs[i] = stream.Next()
+		}
+		return txs
+	}
+
+	Context("when creating a stream", func() {
+		It("should return an error for invalid weights", func() {
+			_, err := txutil.NewStream(txutil.StreamOptions{AssetWeights: map[multichain.Asset]float64{multichain.BTC: -1}})
+			Expect(err).To(HaveOccurred())
+			_, err = txutil.NewStream(txutil.StreamOptions{RouteWeights: map[txutil.RouteKind]float64{
+				txutil.RouteLockMint:    0,
+				txutil.RouteBurnRelease: 0,
+				txutil.RouteBurnMint:    0,
+			}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when generating transactions", func() {
+		It("should generate good transactions", func() {
+			stream, err := txutil.NewStream(txutil.StreamOptions{Seed: GinkgoRandomSeed()})
+			Expect(err).ToNot(HaveOccurred())
+			for _, transaction := range collect(stream, 100) {
+				hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transaction.Hash).To(Equal(hash))
+				Expect(transaction.Input.Get("payload")).To(Equal(pack.Bytes{}))
+			}
+		})
+
+		It("should be reproducible", func() {
+			opts := txutil.StreamOptions{Seed: GinkgoRandomSeed(), PayloadSize: txutil.UniformDistribution(0, 100)}
+			stream, err := txutil.NewStream(opts)
+			Expect(err).ToNot(HaveOccurred())
+			other, err := txutil.NewStream(opts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(collect(stream, 50)).To(Equal(collect(other, 50)))
+
+			opts.Seed++
+			other, err = txutil.NewStream(opts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(collect(stream, 50)).ToNot(Equal(collect(other, 50)))
+		})
+
+		It("should follow the asset and route weights", func() {
+			stream, err := txutil.NewStream(txutil.StreamOptions{
+				Seed: GinkgoRandomSeed(),
+				AssetWeights: map[multichain.Asset]float64{
+					multichain.BTC:  3,
+					multichain.ZEC:  1,
+					multichain.BCH:  0,
+					multichain.DGB:  0,
+					multichain.DOGE: 0,
+					multichain.FIL:  0,
+					multichain.LUNA: 0,
+				},
+				RouteWeights: map[txutil.RouteKind]float64{txutil.RouteBurnMint: 0},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			counts := map[multichain.Asset]int{}
+			for _, transaction := range collect(stream, 2000) {
+				Expect(transaction.Selector.Asset()).To(Or(Equal(multichain.BTC), Equal(multichain.ZEC)))
+				Expect(txutil.KindOfSelector(transaction.Selector)).ToNot(Equal(txutil.RouteBurnMint))
+				counts[transaction.Selector.Asset()]++
+			}
+			Expect(counts[multichain.BTC]).To(BeNumerically("~", 1500, 150))
+		})
+
+		It("should only use the given selectors", func() {
+			selectors := []tx.Selector{"BTC/toEthereum", "BTC/fromSolana"}
+			stream, err := txutil.NewStream(txutil.StreamOptions{Seed: GinkgoRandomSeed(), Selectors: selectors})
+			Expect(err).ToNot(HaveOccurred())
+			for _, transaction := range collect(stream, 100) {
+				Expect(selectors).To(ContainElement(transaction.Selector))
+			}
+		})
+
+		It("should follow the payload size distribution", func() {
+			stream, err := txutil.NewStream(txutil.StreamOptions{
+				Seed:        GinkgoRandomSeed(),
+				PayloadSize: txutil.UniformDistribution(10, 20),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			for _, transaction := range collect(stream, 100) {
+				payload := transaction.Input.Get("payload").(pack.Bytes)
+				Expect(len(payload)).To(BeNumerically(">=", 10))
+				Expect(len(payload)).To(BeNumerically("<", 20))
+				Expect(transaction.Input.Get("phash")).To(Equal(tx.NewPHash(payload)))
+			}
+		})
+
+		It("should clamp huge and non-finite payload sizes", func() {
+			for size, expected := range map[float64]int{
+				1e30:        tx.MaxPayloadBytes,
+				math.Inf(1): 0,
+				math.NaN():  0,
+				-1:          0,
+			} {
+				stream, err := txutil.NewStream(txutil.StreamOptions{
+					Seed:        GinkgoRandomSeed(),
+					PayloadSize: txutil.ConstantDistribution(size),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				var transaction tx.Tx
+				Expect(func() { transaction = stream.Next() }).ToNot(Panic())
+				Expect(transaction.Input.Get("payload").(pack.Bytes)).To(HaveLen(expected))
+			}
+		})
+	})
+
+	Context("when running a stream", func() {
+		It("should emit the same transactions as Next", func() {
+			opts := txutil.StreamOptions{Seed: GinkgoRandomSeed(), Count: 20}
+			stream, err := txutil.NewStream(opts)
+			Expect(err).ToNot(HaveOccurred())
+			other, err := txutil.NewStream(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			txs := []tx.Tx{}
+			for transaction := range stream.Run(context.Background()) {
+				txs = append(txs, transaction)
+			}
+			Expect(txs).To(Equal(collect(other, 20)))
+		})
+
+		It("should emit transactions at the arrival rate", func() {
+			stream, err := txutil.NewStream(txutil.StreamOptions{
+				Seed:     GinkgoRandomSeed(),
+				Interval: txutil.ConstantDistribution(0.01),
+				Count:    20,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			start := time.Now()
+			n := 0
+			for range stream.Run(context.Background()) {
+				n++
+			}
+			Expect(n).To(Equal(20))
+			Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		})
+
+		It("should stop when the context is done", func() {
+			stream, err := txutil.NewStream(txutil.StreamOptions{
+				Seed:     GinkgoRandomSeed(),
+				Interval: txutil.PoissonArrivals(1000),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			txs := stream.Run(ctx)
+			Eventually(txs, time.Second).Should(BeClosed())
+		})
+	})
+})
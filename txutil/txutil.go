@@ -1,7 +1,6 @@
 package txutil
 
 import (
-	"fmt"
 	"math/rand"
 	"strings"
 
@@ -57,8 +56,15 @@ func RandomGoodTx(r *rand.Rand) tx.Tx {
 	// Generate random transaction inputs.
 	input := RandomTxInput(r)
 
+	return NewGoodTx(randomSelector, input)
+}
+
+// NewGoodTx returns a version 1 transaction with the given selector and inputs,
+// an empty output, and a correct hash. It panics if the transaction cannot be
+// hashed.
+func NewGoodTx(selector tx.Selector, input pack.Typed) tx.Tx {
 	// Construct the transaction.
-	transaction, err := tx.NewTx(randomSelector, input)
+	transaction, err := tx.NewTx(selector, input)
 	if err != nil {
 		panic(err)
 	}
@@ -121,7 +127,13 @@ func RandomGoodTxVersion(r *rand.Rand) tx.Version {
 }
 
 func RandomGoodTxSelector(r *rand.Rand) tx.Selector {
-	selectors := AllSelectors()
+	return RandomGoodTxSelectorForRegistry(r, DefaultNetworkRegistry())
+}
+
+// RandomGoodTxSelectorForRegistry returns a random selector of the registry. It
+// panics if the registry has no selectors.
+func RandomGoodTxSelectorForRegistry(r *rand.Rand, registry tx.NetworkRegistry) tx.Selector {
+	selectors := AllSelectorsForRegistry(registry)
 	return selectors[r.Intn(len(selectors))]
 }
 
@@ -197,179 +209,87 @@ func TxsWithStatusToTxs(txsWithStatus []tx.WithStatus) []tx.Tx {
 	return txs
 }
 
+// DefaultNetworkRegistry returns the registry that is used by the selector
+// enumerations in this package that do not take a registry. It is the testnet
+// registry from the default registry, because that network supports the most
+// host chains. To enumerate the selectors of another registry, use the
+// ForRegistry variants of the enumerations.
+func DefaultNetworkRegistry() tx.NetworkRegistry {
+	registry, _ := tx.DefaultRegistry().Network(multichain.NetworkTestnet)
+	return registry
+}
+
 func SupportedAssets() []multichain.Asset {
-	return []multichain.Asset{
-		multichain.BCH, multichain.BTC, multichain.DGB, multichain.DOGE,
-		multichain.FIL, multichain.LUNA, multichain.ZEC,
-	}
+	return DefaultNetworkRegistry().Assets
 }
 
 func SupportedHostChains() []multichain.Chain {
-	return []multichain.Chain{
-		multichain.Arbitrum, multichain.Avalanche, multichain.BinanceSmartChain,
-		multichain.Ethereum, multichain.Fantom, multichain.Goerli,
-		multichain.Moonbeam, multichain.Polygon, multichain.Solana,
-	}
+	return DefaultNetworkRegistry().HostChains
 }
 
 func AllSelectors() []tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make([]tx.Selector, 0, 2*len(assets)*len(hosts))
-	for _, asset := range assets {
-		for _, host := range hosts {
-			host := host
-			selectors = append(selectors, tx.Selector(fmt.Sprintf("%v/to%v", asset, host)))
-			selectors = append(selectors, tx.Selector(fmt.Sprintf("%v/from%v", asset, host)))
-
-			for _, otherHost := range hosts {
-				otherHost := otherHost
-				if otherHost == host {
-					continue
-				}
-				selectors = append(selectors, tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, otherHost)))
-			}
-		}
-	}
-	return selectors
+	return AllSelectorsForRegistry(DefaultNetworkRegistry())
 }
 
 func SelectorsByAsset() map[multichain.Asset][]tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make(map[multichain.Asset][]tx.Selector)
-
-	// Populate all selectors by specific asset.
-	for _, asset := range assets {
-		selectors[asset] = make([]tx.Selector, 0, 2*len(hosts))
-		for _, host := range hosts {
-			host := host
-			selectors[asset] = append(selectors[asset], tx.Selector(fmt.Sprintf("%v/to%v", asset, host)))
-			selectors[asset] = append(selectors[asset], tx.Selector(fmt.Sprintf("%v/from%v", asset, host)))
-			for _, otherHost := range hosts {
-				otherHost := otherHost
-				if otherHost == host {
-					continue
-				}
-				selectors[asset] = append(selectors[asset], tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, otherHost)))
-			}
-		}
-	}
-	return selectors
+	return SelectorsByAssetForRegistry(DefaultNetworkRegistry())
 }
 
+// SelectorsByDestination returns the selectors of DefaultNetworkRegistry,
+// grouped by destination chain. Burn-and-mint selectors that burn and mint on
+// the same host chain (for example, "BTC/toEthereumFromEthereum") are not
+// included, because they are not valid.
 func SelectorsByDestination() map[multichain.Chain][]tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make(map[multichain.Chain][]tx.Selector)
-
-	// Populate asset/toHost selectors.
-	for _, host := range hosts {
-		selectors[host] = make([]tx.Selector, 0, len(assets))
-		for _, asset := range assets {
-			selectors[host] = append(selectors[host], tx.Selector(fmt.Sprintf("%v/to%v", asset, host)))
-		}
-	}
-
-	// Populate asset/fromHost selectors.
-	for _, asset := range assets {
-		assetChain := asset.OriginChain()
-		selectors[assetChain] = make([]tx.Selector, 0, len(hosts))
-		for _, host := range hosts {
-			selectors[assetChain] = append(selectors[assetChain], tx.Selector(fmt.Sprintf("%v/from%v", asset, host)))
-		}
-	}
-
-	// Populate asset/toHostFromOtherHost selectors.
-	for _, host := range hosts {
-		for _, otherHost := range hosts {
-			for _, asset := range assets {
-				selectors[host] = append(selectors[host], tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, otherHost)))
-			}
-		}
-	}
-
-	return selectors
+	return SelectorsByDestinationForRegistry(DefaultNetworkRegistry())
 }
 
+// SelectorsBySource returns the selectors of DefaultNetworkRegistry, grouped
+// by source chain. Like SelectorsByDestination, it does not include
+// burn-and-mint selectors that burn and mint on the same host chain.
 func SelectorsBySource() map[multichain.Chain][]tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make(map[multichain.Chain][]tx.Selector)
-
-	// Populate asset/toHost selectors.
-	for _, asset := range assets {
-		assetChain := asset.OriginChain()
-		selectors[assetChain] = make([]tx.Selector, 0, len(hosts))
-		for _, host := range hosts {
-			selectors[assetChain] = append(selectors[assetChain], tx.Selector(fmt.Sprintf("%v/to%v", asset, host)))
-		}
-	}
+	return SelectorsBySourceForRegistry(DefaultNetworkRegistry())
+}
 
-	// Populate asset/fromHost selectors.
-	for _, host := range hosts {
-		selectors[host] = make([]tx.Selector, len(assets))
-		for i, asset := range assets {
-			selectors[host][i] = tx.Selector(fmt.Sprintf("%v/from%v", asset, host))
-		}
-	}
+func LockSelectors() map[multichain.Chain][]tx.Selector {
+	return LockSelectorsForRegistry(DefaultNetworkRegistry())
+}
 
-	// Populate asset/toHostFromOtherHost selectors.
-	for _, host := range hosts {
-		for _, otherHost := range hosts {
-			for _, asset := range assets {
-				selectors[host] = append(selectors[host], tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, otherHost, host)))
-			}
-		}
-	}
+func NonLockSelectors() map[multichain.Chain][]tx.Selector {
+	return NonLockSelectorsForRegistry(DefaultNetworkRegistry())
+}
 
-	return selectors
+// AllSelectorsForRegistry returns the selectors for all lock-and-mint,
+// burn-and-release, and burn-and-mint routes that are allowed by the registry.
+func AllSelectorsForRegistry(registry tx.NetworkRegistry) []tx.Selector {
+	return registry.AllSelectors()
 }
 
-func LockSelectors() map[multichain.Chain][]tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make(map[multichain.Chain][]tx.Selector)
-
-	// Populate asset/toHost selectors.
-	for _, asset := range assets {
-		assetChain := asset.OriginChain()
-		selectors[assetChain] = make([]tx.Selector, 0, len(hosts))
-		for _, host := range hosts {
-			selectors[assetChain] = append(selectors[assetChain], tx.Selector(fmt.Sprintf("%v/to%v", asset, host)))
-		}
-	}
+// SelectorsByAssetForRegistry returns the selectors of the registry, grouped by
+// asset.
+func SelectorsByAssetForRegistry(registry tx.NetworkRegistry) map[multichain.Asset][]tx.Selector {
+	return registry.SelectorsByAsset()
+}
 
-	return selectors
+// SelectorsByDestinationForRegistry returns the selectors of the registry,
+// grouped by destination chain.
+func SelectorsByDestinationForRegistry(registry tx.NetworkRegistry) map[multichain.Chain][]tx.Selector {
+	return registry.SelectorsByDestination()
 }
 
-func NonLockSelectors() map[multichain.Chain][]tx.Selector {
-	assets := SupportedAssets()
-	hosts := SupportedHostChains()
-	selectors := make(map[multichain.Chain][]tx.Selector)
-
-	// Populate asset/fromHost selectors.
-	for _, asset := range assets {
-		assetChain := asset.OriginChain()
-		for _, host := range hosts {
-			selectors[assetChain] = append(selectors[assetChain], tx.Selector(fmt.Sprintf("%v/from%v", asset, host)))
-		}
-	}
+// SelectorsBySourceForRegistry returns the selectors of the registry, grouped
+// by source chain.
+func SelectorsBySourceForRegistry(registry tx.NetworkRegistry) map[multichain.Chain][]tx.Selector {
+	return registry.SelectorsBySource()
+}
 
-	// Populate asset/toHostFromOtherHost selectors.
-	for _, asset := range assets {
-		assetChain := asset.OriginChain()
-		for _, host := range hosts {
-			host := host
-			for _, otherHost := range hosts {
-				otherHost := otherHost
-				if otherHost == host {
-					continue
-				}
-				selectors[assetChain] = append(selectors[assetChain], tx.Selector(fmt.Sprintf("%v/to%vFrom%v", asset, host, otherHost)))
-			}
-		}
-	}
+// LockSelectorsForRegistry returns the lock-and-mint selectors of the
+// registry, grouped by origin chain.
+func LockSelectorsForRegistry(registry tx.NetworkRegistry) map[multichain.Chain][]tx.Selector {
+	return registry.LockSelectors()
+}
 
-	return selectors
+// NonLockSelectorsForRegistry returns the burn-and-release and burn-and-mint
+// selectors of the registry, grouped by origin chain.
+func NonLockSelectorsForRegistry(registry tx.NetworkRegistry) map[multichain.Chain][]tx.Selector {
+	return registry.NonLockSelectors()
 }
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing/quick"
 
+	"github.com/renproject/multichain"
 	"github.com/renproject/tx"
 	"github.com/renproject/tx/txutil"
 
@@ -82,4 +83,81 @@ var _ = Describe("Transaction Utility", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("when enumerating selectors", func() {
+		It("should return the selectors of the default network registry", func() {
+			assets := txutil.SupportedAssets()
+			hosts := txutil.SupportedHostChains()
+			Expect(assets).To(HaveLen(7))
+			Expect(hosts).To(HaveLen(9))
+
+			// Each asset can be locked to, and released from, each host, and
+			// burned on each host and minted to every other host.
+			Expect(txutil.AllSelectors()).To(HaveLen(len(assets) * len(hosts) * (len(hosts) + 1)))
+			Expect(txutil.SelectorsByAsset()[multichain.BTC]).To(HaveLen(len(hosts) * (len(hosts) + 1)))
+			Expect(txutil.LockSelectors()[multichain.Bitcoin]).To(HaveLen(len(hosts)))
+			Expect(txutil.NonLockSelectors()[multichain.Bitcoin]).To(HaveLen(len(hosts) * len(hosts)))
+
+			// Grouping by destination or source does not include selectors
+			// that burn and mint on the same host.
+			byDestination := txutil.SelectorsByDestination()
+			Expect(byDestination).To(HaveLen(len(assets) + len(hosts)))
+			Expect(byDestination[multichain.Bitcoin]).To(HaveLen(len(hosts)))
+			Expect(byDestination[multichain.Ethereum]).To(HaveLen(len(assets) * len(hosts)))
+			Expect(byDestination[multichain.Ethereum][0]).To(Equal(tx.Selector("BCH/toEthereum")))
+			Expect(byDestination[multichain.Ethereum]).ToNot(ContainElement(tx.Selector("BTC/toEthereumFromEthereum")))
+			Expect(byDestination[multichain.Ethereum]).To(ContainElement(tx.Selector("BTC/toEthereumFromSolana")))
+
+			bySource := txutil.SelectorsBySource()
+			Expect(bySource).To(HaveLen(len(assets) + len(hosts)))
+			Expect(bySource[multichain.Bitcoin]).To(HaveLen(len(hosts)))
+			Expect(bySource[multichain.Ethereum]).To(HaveLen(len(assets) * len(hosts)))
+			Expect(bySource[multichain.Ethereum][0]).To(Equal(tx.Selector("BCH/fromEthereum")))
+			Expect(bySource[multichain.Ethereum]).ToNot(ContainElement(tx.Selector("BTC/toEthereumFromEthereum")))
+			Expect(bySource[multichain.Ethereum]).To(ContainElement(tx.Selector("BTC/toSolanaFromEthereum")))
+
+			registry := txutil.DefaultNetworkRegistry()
+			Expect(txutil.AllSelectors()).To(Equal(txutil.AllSelectorsForRegistry(registry)))
+			Expect(byDestination).To(Equal(txutil.SelectorsByDestinationForRegistry(registry)))
+			Expect(bySource).To(Equal(txutil.SelectorsBySourceForRegistry(registry)))
+		})
+
+		It("should return the selectors of the given registry", func() {
+			registry := tx.NetworkRegistry{
+				Assets:     []multichain.Asset{multichain.BTC},
+				HostChains: []multichain.Chain{multichain.Ethereum, multichain.Solana},
+				Routes: []tx.Route{
+					{Asset: multichain.BTC, From: multichain.Bitcoin, To: multichain.Ethereum},
+					{Asset: multichain.BTC, From: multichain.Ethereum, To: multichain.Bitcoin},
+					{Asset: multichain.BTC, From: multichain.Ethereum, To: multichain.Solana},
+				},
+			}
+			Expect(registry.Validate()).To(Succeed())
+			Expect(txutil.AllSelectorsForRegistry(registry)).To(Equal([]tx.Selector{"BTC/toEthereum", "BTC/fromEthereum", "BTC/toSolanaFromEthereum"}))
+			Expect(txutil.SelectorsByAssetForRegistry(registry)).To(Equal(map[multichain.Asset][]tx.Selector{
+				multichain.BTC: {"BTC/toEthereum", "BTC/fromEthereum", "BTC/toSolanaFromEthereum"},
+			}))
+			Expect(txutil.SelectorsByDestinationForRegistry(registry)).To(Equal(map[multichain.Chain][]tx.Selector{
+				multichain.Ethereum: {"BTC/toEthereum"},
+				multichain.Bitcoin:  {"BTC/fromEthereum"},
+				multichain.Solana:   {"BTC/toSolanaFromEthereum"},
+			}))
+			Expect(txutil.SelectorsBySourceForRegistry(registry)).To(Equal(map[multichain.Chain][]tx.Selector{
+				multichain.Bitcoin:  {"BTC/toEthereum"},
+				multichain.Ethereum: {"BTC/fromEthereum", "BTC/toSolanaFromEthereum"},
+				multichain.Solana:   nil,
+			}))
+			Expect(txutil.LockSelectorsForRegistry(registry)).To(Equal(map[multichain.Chain][]tx.Selector{
+				multichain.Bitcoin: {"BTC/toEthereum"},
+			}))
+			Expect(txutil.NonLockSelectorsForRegistry(registry)).To(Equal(map[multichain.Chain][]tx.Selector{
+				multichain.Bitcoin: {"BTC/fromEthereum", "BTC/toSolanaFromEthereum"},
+			}))
+
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				Expect(txutil.AllSelectorsForRegistry(registry)).To(ContainElement(txutil.RandomGoodTxSelectorForRegistry(r, registry)))
+			}
+		})
+	})
 })
@@ -0,0 +1,301 @@
+package txutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/big"
+	"math/rand"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+	"github.com/renproject/tx"
+)
+
+// A TestVector is a golden vector for transaction hashing. SDKs in other
+// languages can use test vectors to check that they compute the same
+// transaction hashes as this package, byte for byte.
+type TestVector struct {
+	// Name describes what the vector covers.
+	Name string `json:"name"`
+	// Version, Selector, and Input are the hashed fields of the transaction.
+	// The input is in the canonical JSON encoding of pack.Typed.
+	Version  tx.Version      `json:"version"`
+	Selector tx.Selector     `json:"selector"`
+	Input    json.RawMessage `json:"in"`
+	// Surge is the hex encoded surge binary that is hashed: the version,
+	// selector, and input, in that order. It is padded with zeros to the
+	// surge size hint of these fields, because tx.NewTxHash hashes the whole
+	// buffer, and the size hint of some inputs (such as nested lists) is
+	// larger than their encoding.
+	Surge string `json:"surge"`
+	// Hash is the hex encoded SHA256 hash of the surge binary.
+	Hash string `json:"hash"`
+}
+
+// NewTestVector returns the test vector for a transaction.
+func NewTestVector(name string, version tx.Version, selector tx.Selector, input pack.Typed) (TestVector, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return TestVector{}, fmt.Errorf("marshaling input: %v", err)
+	}
+	if data, err = tx.CanonicalizeJSON(data); err != nil {
+		return TestVector{}, fmt.Errorf("canonicalizing input: %v", err)
+	}
+	preimage, err := hashPreimage(version, selector, input)
+	if err != nil {
+		return TestVector{}, err
+	}
+	hash, err := tx.NewTxHash(version, selector, input)
+	if err != nil {
+		return TestVector{}, fmt.Errorf("hashing: %v", err)
+	}
+	return TestVector{
+		Name:     name,
+		Version:  version,
+		Selector: selector,
+		Input:    data,
+		Surge:    hex.EncodeToString(preimage),
+		Hash:     hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// Check recomputes the surge binary and hash of the vector from its version,
+// selector, and input, and returns an error if either has drifted.
+func (vector TestVector) Check() error {
+	input := pack.Typed{}
+	if err := json.Unmarshal(vector.Input, &input); err != nil {
+		return fmt.Errorf("%v: unmarshaling input: %v", vector.Name, err)
+	}
+	expected, err := NewTestVector(vector.Name, vector.Version, vector.Selector, input)
+	if err != nil {
+		return fmt.Errorf("%v: %v", vector.Name, err)
+	}
+	if !bytes.Equal(expected.Input, vector.Input) {
+		return fmt.Errorf("%v: bad input: expected %s, got %s", vector.Name, expected.Input, vector.Input)
+	}
+	if expected.Surge != vector.Surge {
+		return fmt.Errorf("%v: bad surge: expected %v, got %v", vector.Name, expected.Surge, vector.Surge)
+	}
+	if expected.Hash != vector.Hash {
+		return fmt.Errorf("%v: bad hash: expected %v, got %v", vector.Name, expected.Hash, vector.Hash)
+	}
+	// The hash is defined as the SHA256 hash of the surge binary, and SDKs
+	// rely on this, so it is checked independently of tx.NewTxHash.
+	preimage, err := hex.DecodeString(vector.Surge)
+	if err != nil {
+		return fmt.Errorf("%v: decoding surge: %v", vector.Name, err)
+	}
+	if hash := sha256.Sum256(preimage); hex.EncodeToString(hash[:]) != vector.Hash {
+		return fmt.Errorf("%v: hash is not the SHA256 hash of the surge binary", vector.Name)
+	}
+	return nil
+}
+
+// TestVectors returns the corpus of test vectors. There is one vector for
+// every selector in AllSelectors, with a small random input that is seeded by
+// the selector, and one vector for each edge case: every version, every pack
+// type, minimum and maximum values, empty and large values, and the inputs of
+// intrinsic transactions. The corpus is deterministic. It is only used to
+// write new golden vectors: existing golden vectors should be checked with
+// TestVector.Check, and not compared against the corpus, because the corpus
+// changes whenever the generators change.
+func TestVectors() ([]TestVector, error) {
+	vectors := []TestVector{}
+	for _, selector := range AllSelectors() {
+		h := fnv.New64a()
+		h.Write([]byte(selector))
+		r := rand.New(rand.NewSource(int64(h.Sum64())))
+		input := pack.NewTyped(
+			"amount", pack.NewU256FromUint64(r.Uint64()),
+			"nonce", pack.Bytes32{}.Generate(r, 1).Interface().(pack.Bytes32),
+			"to", pack.String(fmt.Sprintf("%x", r.Uint64())),
+		)
+		vector, err := NewTestVector(string(selector), tx.Version1, selector, input)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	for _, edgeCase := range edgeCaseInputs() {
+		vector, err := NewTestVector(edgeCase.name, edgeCase.version, edgeCase.selector, edgeCase.input)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// WriteTestVectors writes the corpus of test vectors to a writer as a JSON
+// array, with one vector per line. Inputs are written exactly as they are in
+// the vectors, so that they remain in their canonical encoding.
+func WriteTestVectors(w io.Writer) error {
+	vectors, err := TestVectors()
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteString("[\n")
+	for i, vector := range vectors {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		if err := enc.Encode(vector); err != nil {
+			return fmt.Errorf("encoding %v: %v", vector.Name, err)
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteString("\n]\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ReadTestVectors reads a corpus of test vectors from a reader.
+func ReadTestVectors(r io.Reader) ([]TestVector, error) {
+	vectors := []TestVector{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&vectors); err != nil {
+		return nil, fmt.Errorf("decoding test vectors: %v", err)
+	}
+	return vectors, nil
+}
+
+type edgeCaseInput struct {
+	name     string
+	version  tx.Version
+	selector tx.Selector
+	input    pack.Typed
+}
+
+func edgeCaseInputs() []edgeCaseInput {
+	r := rand.New(rand.NewSource(0))
+	standard := RandomTxInput(r)
+
+	maxU128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	bytes32, bytes65 := [32]byte{}, [65]byte{}
+	for i := range bytes32 {
+		bytes32[i] = 0xff
+	}
+	for i := range bytes65 {
+		bytes65[i] = 0xff
+	}
+	large := make([]byte, 4096)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	list, err := pack.NewList(pack.NewU64(1), pack.NewU64(2), pack.NewU64(3))
+	if err != nil {
+		panic(err)
+	}
+	listOfStructs, err := pack.NewList(
+		pack.NewStruct("a", pack.NewU8(1), "b", pack.String("x")),
+		pack.NewStruct("a", pack.NewU8(2), "b", pack.String("y")),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	edgeCases := []edgeCaseInput{
+		{"version-empty", "", "BTC/toEthereum", standard},
+		{"version-0", tx.Version0, "BTC/toEthereum", standard},
+		{"version-1", tx.Version1, "BTC/toEthereum", standard},
+		{"selector-empty", tx.Version1, "", standard},
+		{"input-empty", tx.Version1, "BTC/toEthereum", pack.NewTyped()},
+		{"types-zero", tx.Version1, "BTC/toEthereum", pack.NewTyped(
+			"bool", pack.NewBool(false),
+			"u8", pack.NewU8(0),
+			"u16", pack.NewU16(0),
+			"u32", pack.NewU32(0),
+			"u64", pack.NewU64(0),
+			"u128", pack.NewU128FromUint64(0),
+			"u256", pack.NewU256FromUint64(0),
+			"string", pack.String(""),
+			"bytes", pack.Bytes{},
+			"bytes32", pack.Bytes32{},
+			"bytes65", pack.Bytes65{},
+			"struct", pack.NewStruct(),
+		)},
+		{"types-max", tx.Version1, "BTC/toEthereum", pack.NewTyped(
+			"bool", pack.NewBool(true),
+			"u8", pack.NewU8(0xff),
+			"u16", pack.NewU16(0xffff),
+			"u32", pack.NewU32(0xffffffff),
+			"u64", pack.NewU64(0xffffffffffffffff),
+			"u128", pack.NewU128FromInt(maxU128),
+			"u256", pack.MaxU256,
+			"string", pack.String("\u0000\"\\\né世\U0001f600"),
+			"bytes", pack.NewBytes(large),
+			"bytes32", pack.NewBytes32(bytes32),
+			"bytes65", pack.NewBytes65(bytes65),
+		)},
+		{"types-nested", tx.Version1, "BTC/toEthereum", pack.NewTyped(
+			"list", list,
+			"structs", listOfStructs,
+			"struct", pack.NewStruct(
+				"inner", pack.NewStruct("value", pack.NewU32(1)),
+				"list", list,
+			),
+		)},
+		{"field-order", tx.Version1, "BTC/toEthereum", pack.NewTyped(
+			"z", pack.NewU8(1),
+			"a", pack.NewU8(2),
+		)},
+		{"string-long", tx.Version1, "BTC/toEthereum", pack.NewTyped(
+			"to", pack.String(string(bytes.Repeat([]byte("0123456789abcdef"), 256))),
+		)},
+	}
+
+	intrinsics := []struct {
+		fn    string
+		input interface{}
+	}{
+		{tx.SyncWithChainFn, tx.SyncWithChainInput{
+			LatestHeight: 1,
+			GasLimit:     pack.NewU256FromUint64(21000),
+			GasCap:       pack.NewU256FromUint64(1000000000),
+			GasPrice:     pack.NewU256FromUint64(1000000),
+		}},
+		{tx.ChangeFeesFn, tx.ChangeFeesInput{
+			Fees: []tx.ChainFees{{Chain: "Ethereum", UnderlyingFee: pack.NewU256FromUint64(1000), MintFee: 15, BurnFee: 15}},
+		}},
+		{tx.EpochFn, tx.EpochInput{Number: 1, Hash: pack.NewBytes32(bytes32)}},
+		{tx.ChangeSignatoriesFn, tx.ChangeSignatoriesInput{Epoch: 1, PubKey: append([]byte{0x02}, bytes32[:]...)}},
+	}
+	for _, intrinsic := range intrinsics {
+		input, err := tx.EncodeIntrinsicInput(intrinsic.input)
+		if err != nil {
+			panic(err)
+		}
+		edgeCases = append(edgeCases, edgeCaseInput{
+			name:     "intrinsic-" + intrinsic.fn,
+			version:  tx.Version1,
+			selector: tx.Selector("BTC/" + intrinsic.fn),
+			input:    input,
+		})
+	}
+	return edgeCases
+}
+
+func hashPreimage(version tx.Version, selector tx.Selector, input pack.Typed) ([]byte, error) {
+	buf := make([]byte, version.SizeHint()+selector.SizeHint()+input.SizeHint())
+	rem := surge.MaxBytes
+	tail, rem, err := version.Marshal(buf, rem)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling version: %v", err)
+	}
+	if tail, rem, err = selector.Marshal(tail, rem); err != nil {
+		return nil, fmt.Errorf("marshaling selector: %v", err)
+	}
+	if _, _, err = input.Marshal(tail, rem); err != nil {
+		return nil, fmt.Errorf("marshaling input: %v", err)
+	}
+	return buf, nil
+}
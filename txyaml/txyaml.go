@@ -0,0 +1,42 @@
+// Package txyaml loads registries from YAML. It is kept separate from the tx
+// package so that importing transactions does not require importing a YAML
+// parser.
+package txyaml
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/renproject/tx"
+	"gopkg.in/yaml.v2"
+)
+
+// NewRegistry returns the registry that is defined by YAML data. Unknown fields
+// are rejected, and the registry is validated.
+func NewRegistry(data []byte) (tx.Registry, error) {
+	registry := tx.Registry{}
+	if err := yaml.UnmarshalStrict(data, &registry); err != nil {
+		return tx.Registry{}, fmt.Errorf("unmarshaling registry: %v", err)
+	}
+	if err := registry.Validate(); err != nil {
+		return tx.Registry{}, err
+	}
+	return registry, nil
+}
+
+// LoadRegistry reads a registry from a file. Files with a ".yaml" or ".yml"
+// extension are loaded as YAML, and all other files are loaded as JSON.
+func LoadRegistry(filename string) (tx.Registry, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return tx.Registry{}, err
+		}
+		return NewRegistry(data)
+	default:
+		return tx.LoadRegistry(filename)
+	}
+}
@@ -0,0 +1,13 @@
+package txyaml_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTxyaml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Txyaml Suite")
+}
@@ -0,0 +1,89 @@
+package txyaml_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txyaml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("YAML registries", func() {
+
+	registryJSON := `{
+		"networks": {
+			"mainnet": {
+				"assets": ["BTC", "ZEC"],
+				"hostChains": ["Ethereum", "Solana"],
+				"routes": [
+					{"from": "Bitcoin", "to": "Ethereum"},
+					{"asset": "ZEC", "from": "Zcash", "to": "Solana"}
+				]
+			},
+			"testnet": {
+				"assets": ["BTC", "ZEC"],
+				"hostChains": ["Ethereum", "Goerli", "Solana"]
+			}
+		}
+	}`
+	registryYAML := `
+networks:
+  mainnet:
+    assets: [BTC, ZEC]
+    hostChains: [Ethereum, Solana]
+    routes:
+      - {from: Bitcoin, to: Ethereum}
+      - {asset: ZEC, from: Zcash, to: Solana}
+  testnet:
+    assets: [BTC, ZEC]
+    hostChains: [Ethereum, Goerli, Solana]
+`
+
+	Context("when loading a registry", func() {
+		It("should load the same registry from JSON and YAML", func() {
+			fromJSON, err := tx.NewRegistryFromJSON([]byte(registryJSON))
+			Expect(err).ToNot(HaveOccurred())
+			fromYAML, err := txyaml.NewRegistry([]byte(registryYAML))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fromYAML).To(Equal(fromJSON))
+		})
+
+		It("should load a registry from a file based on its extension", func() {
+			dir, err := ioutil.TempDir("", "registry")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "registry.json"), []byte(registryJSON), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "registry.yml"), []byte(registryYAML), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "registry.YAML"), []byte(registryYAML), 0644)).To(Succeed())
+			fromJSON, err := txyaml.LoadRegistry(filepath.Join(dir, "registry.json"))
+			Expect(err).ToNot(HaveOccurred())
+			for _, filename := range []string{"registry.yml", "registry.YAML"} {
+				fromYAML, err := txyaml.LoadRegistry(filepath.Join(dir, filename))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fromYAML).To(Equal(fromJSON))
+			}
+
+			_, err = txyaml.LoadRegistry(filepath.Join(dir, "missing.yml"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the registry is invalid", func() {
+		It("should return an error", func() {
+			for _, data := range []string{
+				"networks:\n  mainnet:\n    assets: [BTC]\n    unknown: true\n",
+				"networks:\n  betanet:\n    assets: [BTC]\n    hostChains: [Ethereum]\n",
+				"networks:\n  mainnet:\n    assets: [XYZ]\n    hostChains: [Ethereum]\n",
+				"networks: [",
+			} {
+				_, err := txyaml.NewRegistry([]byte(data))
+				Expect(err).To(HaveOccurred(), data)
+			}
+		})
+	})
+})
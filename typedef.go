@@ -0,0 +1,184 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/surge"
+)
+
+// maxTypeDefDepth is the maximum nesting of struct and list types that will be
+// accepted when decoding type definitions. It prevents maliciously deep types
+// from exhausting the stack.
+const maxTypeDefDepth = 64
+
+// A TypeDef is a transparent definition of a pack.Type. The pack package does
+// not expose the fields of struct types, or the element types of list types,
+// so this is recovered from the binary representation of the type (which is
+// part of the transaction hash, and so will never change). It is used to
+// convert types into other encodings, such as CBOR and protocol buffers.
+type TypeDef struct {
+	Kind   pack.Kind
+	Fields []TypeDefField // Only used by struct types.
+	Elem   *TypeDef       // Only used by list types.
+}
+
+// TypeDefField is the name and type definition of a field in a struct type.
+type TypeDefField struct {
+	Name string
+	Type TypeDef
+}
+
+// NewTypeDef returns the definition of a type.
+func NewTypeDef(t pack.Type) (TypeDef, error) {
+	buf, err := marshalType(t)
+	if err != nil {
+		return TypeDef{}, err
+	}
+	def := TypeDef{}
+	if _, _, err := def.unmarshal(buf, len(buf), 0); err != nil {
+		return TypeDef{}, err
+	}
+	return def, nil
+}
+
+// PackType returns the type that is defined. An error is returned if the
+// definition is not complete, for example, if a list has no element type.
+func (def TypeDef) PackType() (pack.Type, error) {
+	buf := make([]byte, def.sizeHint())
+	if _, _, err := def.marshal(buf, len(buf)); err != nil {
+		return nil, err
+	}
+	var t pack.Type
+	if _, _, err := pack.UnmarshalType(&t, buf, len(buf)); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// marshalType returns the binary representation of a type.
+func marshalType(t pack.Type) ([]byte, error) {
+	if t == nil {
+		return nil, fmt.Errorf("nil type")
+	}
+	buf := make([]byte, pack.SizeHintType(t))
+	if _, _, err := pack.MarshalType(t, buf, len(buf)); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// equalTypes returns true if two types are identical.
+func equalTypes(a, b pack.Type) bool {
+	bufA, err := marshalType(a)
+	if err != nil {
+		return false
+	}
+	bufB, err := marshalType(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(bufA, bufB)
+}
+
+func (def TypeDef) sizeHint() int {
+	switch def.Kind {
+	case pack.KindStruct:
+		total := surge.SizeHintU8 + surge.SizeHintU32
+		for _, field := range def.Fields {
+			total += surge.SizeHintString(field.Name) + field.Type.sizeHint()
+		}
+		return total
+	case pack.KindList:
+		if def.Elem == nil {
+			return surge.SizeHintU8
+		}
+		return surge.SizeHintU8 + def.Elem.sizeHint()
+	default:
+		return surge.SizeHintU8
+	}
+}
+
+func (def TypeDef) marshal(buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	if buf, rem, err = surge.MarshalU8(uint8(def.Kind), buf, rem); err != nil {
+		return buf, rem, err
+	}
+	switch def.Kind {
+	case pack.KindStruct:
+		if buf, rem, err = surge.MarshalU32(uint32(len(def.Fields)), buf, rem); err != nil {
+			return buf, rem, err
+		}
+		for _, field := range def.Fields {
+			if buf, rem, err = surge.MarshalString(field.Name, buf, rem); err != nil {
+				return buf, rem, err
+			}
+			if buf, rem, err = field.Type.marshal(buf, rem); err != nil {
+				return buf, rem, err
+			}
+		}
+	case pack.KindList:
+		if def.Elem == nil {
+			return buf, rem, fmt.Errorf("list type without element type")
+		}
+		return def.Elem.marshal(buf, rem)
+	default:
+		if !isScalarKind(def.Kind) {
+			return buf, rem, fmt.Errorf("unexpected kind %v", def.Kind)
+		}
+	}
+	return buf, rem, nil
+}
+
+func (def *TypeDef) unmarshal(buf []byte, rem int, depth int) ([]byte, int, error) {
+	if depth > maxTypeDefDepth {
+		return buf, rem, fmt.Errorf("type nested too deeply: max depth=%v", maxTypeDefDepth)
+	}
+	var err error
+	var kind uint8
+	if buf, rem, err = surge.UnmarshalU8(&kind, buf, rem); err != nil {
+		return buf, rem, err
+	}
+	def.Kind = pack.Kind(kind)
+	switch def.Kind {
+	case pack.KindStruct:
+		var numFields uint32
+		if buf, rem, err = surge.UnmarshalU32(&numFields, buf, rem); err != nil {
+			return buf, rem, err
+		}
+		def.Fields = make([]TypeDefField, 0)
+		for i := uint32(0); i < numFields; i++ {
+			field := TypeDefField{}
+			if buf, rem, err = surge.UnmarshalString(&field.Name, buf, rem); err != nil {
+				return buf, rem, err
+			}
+			if buf, rem, err = field.Type.unmarshal(buf, rem, depth+1); err != nil {
+				return buf, rem, err
+			}
+			def.Fields = append(def.Fields, field)
+		}
+	case pack.KindList:
+		def.Elem = new(TypeDef)
+		if buf, rem, err = def.Elem.unmarshal(buf, rem, depth+1); err != nil {
+			return buf, rem, err
+		}
+	default:
+		if !isScalarKind(def.Kind) {
+			return buf, rem, fmt.Errorf("unexpected kind %v", kind)
+		}
+	}
+	return buf, rem, nil
+}
+
+// isScalarKind returns true if the kind is not abstract. Scalar kinds fully
+// define their type.
+func isScalarKind(kind pack.Kind) bool {
+	switch kind {
+	case pack.KindBool, pack.KindU8, pack.KindU16, pack.KindU32, pack.KindU64, pack.KindU128, pack.KindU256,
+		pack.KindString, pack.KindBytes, pack.KindBytes32, pack.KindBytes65:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,148 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/pack"
+)
+
+// Errors returned when validating a transaction. Validate wraps exactly one of
+// these errors (or ErrInvalidRecipient), so callers can use errors.Is to find
+// out why a transaction is invalid.
+var (
+	// ErrUnknownVersion is returned when the version of a transaction is not
+	// one of the enumerated versions.
+	ErrUnknownVersion = errors.New("unknown version")
+	// ErrUnknownAsset is returned when the asset of a selector has no origin
+	// chain.
+	ErrUnknownAsset = errors.New("unknown asset")
+	// ErrSameChain is returned when the source and destination of a selector
+	// are the same chain.
+	ErrSameChain = errors.New("same source and destination")
+	// ErrUnsupportedSelector is returned when a selector is not one of the
+	// selectors of the network registry.
+	ErrUnsupportedSelector = errors.New("unsupported selector")
+	// ErrMissingInput is returned when an input that is required by the
+	// selector is missing.
+	ErrMissingInput = errors.New("missing input")
+	// ErrInvalidInputType is returned when an input has the wrong type.
+	ErrInvalidInputType = errors.New("invalid input type")
+	// ErrInvalidNHash is returned when the nhash is not derived from the
+	// nonce, txid, and txindex (see NewNHash).
+	ErrInvalidNHash = errors.New("invalid nhash")
+	// ErrInvalidHash is returned when the hash of a transaction is not the
+	// hash of its version, selector, and input.
+	ErrInvalidHash = errors.New("invalid hash")
+)
+
+// CrossChainInputTypes returns the inputs that are required by cross-chain
+// transactions, and their types. Transactions may have other inputs.
+func CrossChainInputTypes() pack.Typed {
+	return pack.NewTyped(
+		"txid", pack.Bytes{},
+		"txindex", pack.U32(0),
+		"amount", pack.U256{},
+		"payload", pack.Bytes{},
+		"phash", pack.Bytes32{},
+		"to", pack.String(""),
+		"nonce", pack.Bytes32{},
+		"nhash", pack.Bytes32{},
+		"gpubkey", pack.Bytes{},
+		"ghash", pack.Bytes32{},
+	)
+}
+
+// Validate returns an error if the transaction is not valid on a network. The
+// checks are done in order, from cheapest to most expensive:
+//   - the version must be known,
+//   - the selector must be intrinsic, an extrinsic function (claimFees,
+//     claimFeesFromEvent, or returnStateAndOutputs) of an asset of the
+//     registry, or one of the selectors of the registry,
+//   - the inputs must have the expected types,
+//   - the recipient must be valid on the destination chain,
+//   - the nhash must be derived from the other inputs,
+//   - the hash must be the hash of the version, selector, and input.
+//
+// The output of the transaction is not validated, and neither is its size.
+// Untrusted transactions should be checked against a policy (see CheckPolicy)
+// first, because hashing a very large transaction is expensive.
+func (tx Tx) Validate(registry NetworkRegistry) error {
+	if tx.Version.String() == "" || tx.Version.String() != string(tx.Version) {
+		return fmt.Errorf("%w %q", ErrUnknownVersion, tx.Version)
+	}
+	if tx.Selector.IsIntrinsic() {
+		if _, err := tx.IntrinsicInput(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInputType, err)
+		}
+		return tx.VerifyHash()
+	}
+	if isExtrinsic(tx.Selector) {
+		// The inputs of extrinsic transactions are not defined by this
+		// package, so only their asset and hash are validated.
+		if err := validateExtrinsicSelector(registry, tx.Selector); err != nil {
+			return err
+		}
+		return tx.VerifyHash()
+	}
+	if err := validateSelector(registry, tx.Selector); err != nil {
+		return err
+	}
+	if err := validateInputTypes(tx.Input, CrossChainInputTypes()); err != nil {
+		return err
+	}
+	if err := tx.ValidateRecipient(); err != nil {
+		return err
+	}
+	nhash := NewNHash(
+		tx.Input.Get("nonce").(pack.Bytes32),
+		tx.Input.Get("txid").(pack.Bytes),
+		tx.Input.Get("txindex").(pack.U32).Uint32(),
+	)
+	if got := tx.Input.Get("nhash").(pack.Bytes32); got != nhash {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidNHash, nhash, got)
+	}
+	return tx.VerifyHash()
+}
+
+func validateSelector(registry NetworkRegistry, selector Selector) error {
+	if selector.Asset().OriginChain() == "" {
+		return fmt.Errorf("%w %q in selector %v", ErrUnknownAsset, selector.Asset(), selector)
+	}
+	if selector.Source() != "" && selector.Source() == selector.Destination() {
+		return fmt.Errorf("%w %v in selector %v", ErrSameChain, selector.Source(), selector)
+	}
+	if !registry.AllowsSelector(selector) {
+		return fmt.Errorf("%w %v", ErrUnsupportedSelector, selector)
+	}
+	return nil
+}
+
+// isExtrinsic returns true if the selector is for one of the extrinsic
+// functions that are generated by the network, or by Darknode operators.
+func isExtrinsic(selector Selector) bool {
+	return selector.IsClaimFees() || selector.IsClaimFeesFromEvent() || selector.IsReturnStateAndOutputs()
+}
+
+func validateExtrinsicSelector(registry NetworkRegistry, selector Selector) error {
+	if selector.Asset().OriginChain() == "" {
+		return fmt.Errorf("%w %q in selector %v", ErrUnknownAsset, selector.Asset(), selector)
+	}
+	if !registry.hasAsset(selector.Asset()) {
+		return fmt.Errorf("%w %v", ErrUnsupportedSelector, selector)
+	}
+	return nil
+}
+
+func validateInputTypes(input, expected pack.Typed) error {
+	for _, field := range expected {
+		value := input.Get(field.Name)
+		if value == nil {
+			return fmt.Errorf("%w %q", ErrMissingInput, field.Name)
+		}
+		if !equalTypes(value.Type(), field.Value.Type()) {
+			return fmt.Errorf("%w: expected input %q of type %v, got %v", ErrInvalidInputType, field.Name, field.Value.Type(), value.Type())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package tx_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/multichain"
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validation", func() {
+
+	registry := txutil.DefaultNetworkRegistry()
+
+	// mutate returns a copy of a transaction with its input changed, and its
+	// hash recomputed.
+	mutate := func(transaction tx.Tx, name string, value pack.Value) tx.Tx {
+		input := make(pack.Typed, 0, len(transaction.Input))
+		for _, field := range transaction.Input {
+			if field.Name == name {
+				if value == nil {
+					continue
+				}
+				field = pack.NewStructField(name, value)
+			}
+			input = append(input, field)
+		}
+		transaction.Input = input
+		hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+		Expect(err).ToNot(HaveOccurred())
+		transaction.Hash = hash
+		return transaction
+	}
+
+	Context("when validating coherent transactions", func() {
+		It("should succeed", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 100; i++ {
+				Expect(txutil.RandomValidTx(r).Validate(registry)).To(Succeed())
+			}
+		})
+
+		It("should succeed for intrinsic transactions", func() {
+			transaction, err := tx.NewEpochTx("BTC", tx.EpochInput{Number: 1, Hash: pack.Bytes32{1}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transaction.Validate(registry)).To(Succeed())
+		})
+
+		It("should succeed for extrinsic transactions", func() {
+			for _, fn := range []string{tx.ClaimFeesFn, tx.ClaimFeesFromEventFn, tx.ReturnStateAndOutputsFn} {
+				transaction, err := tx.NewTx(tx.Selector("BTC/"+fn), pack.NewTyped("amount", pack.NewU256FromU64(1)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transaction.Validate(registry)).To(Succeed(), fn)
+
+				transaction.Hash[0] ^= 1
+				Expect(errors.Is(transaction.Validate(registry), tx.ErrInvalidHash)).To(BeTrue(), fn)
+			}
+		})
+
+		It("should return an error for extrinsic transactions of unsupported assets", func() {
+			for _, fn := range []string{tx.ClaimFeesFn, tx.ClaimFeesFromEventFn, tx.ReturnStateAndOutputsFn} {
+				transaction, err := tx.NewTx(tx.Selector("XBTC/"+fn), pack.NewTyped())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errors.Is(transaction.Validate(registry), tx.ErrUnknownAsset)).To(BeTrue(), fn)
+
+				transaction, err = tx.NewTx(tx.Selector("BTC/"+fn), pack.NewTyped())
+				Expect(err).ToNot(HaveOccurred())
+				empty := tx.NetworkRegistry{Assets: []multichain.Asset{multichain.ETH}}
+				Expect(errors.Is(transaction.Validate(empty), tx.ErrUnsupportedSelector)).To(BeTrue(), fn)
+			}
+		})
+
+		It("should succeed for version 0 transactions", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			transaction.Version = tx.Version0
+			hash, err := tx.NewTxHash(transaction.Version, transaction.Selector, transaction.Input)
+			Expect(err).ToNot(HaveOccurred())
+			transaction.Hash = hash
+			Expect(transaction.Validate(registry)).To(Succeed())
+		})
+
+		It("should allow extra inputs", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			transaction.Input = append(transaction.Input, pack.NewStructField("extra", pack.NewU8(1)))
+			transaction = mutate(transaction, "extra", pack.NewU8(1))
+			Expect(transaction.Validate(registry)).To(Succeed())
+		})
+	})
+
+	Context("when validating malformed transactions", func() {
+		It("should return an error for each category of malformation", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for _, category := range txutil.InvalidTxCategories() {
+				for i := 0; i < 10; i++ {
+					invalid := txutil.NewInvalidTx(r, category)
+					err := invalid.Check(registry)
+					Expect(err).To(HaveOccurred(), "category %v", category)
+					Expect(errors.Is(err, invalid.Err)).To(BeTrue(), "category %v: %v", category, err)
+				}
+			}
+		})
+
+		It("should leave the size of the payload to policies", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			Expect(mutate(transaction, "payload", pack.NewBytes(make([]byte, tx.MaxPayloadBytes))).CheckPolicy(tx.DefaultPolicy())).To(Succeed())
+			oversized := mutate(transaction, "payload", pack.NewBytes(make([]byte, tx.MaxPayloadBytes+1)))
+			Expect(oversized.Validate(registry)).To(Succeed())
+			Expect(errors.Is(oversized.CheckPolicy(tx.DefaultPolicy()), tx.ErrPayloadTooLarge)).To(BeTrue())
+		})
+
+		It("should return an error for selectors that are not in the registry", func() {
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			transaction := txutil.RandomValidTx(r)
+			transaction.Selector = tx.Selector("BTC/toEthereum")
+			transaction = mutate(transaction, "to", pack.String("0x0000000000000000000000000000000000000001"))
+			empty := tx.NetworkRegistry{Assets: []multichain.Asset{multichain.BTC}}
+			err := transaction.Validate(empty)
+			Expect(errors.Is(err, tx.ErrUnsupportedSelector)).To(BeTrue())
+		})
+
+		It("should return an error for an unknown version before anything else", func() {
+			transaction := tx.Tx{Version: "2", Selector: "BTC/toBitcoin"}
+			Expect(errors.Is(transaction.Validate(registry), tx.ErrUnknownVersion)).To(BeTrue())
+			transaction.Version = ""
+			Expect(errors.Is(transaction.Validate(registry), tx.ErrUnknownVersion)).To(BeTrue())
+		})
+	})
+})
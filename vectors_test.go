@@ -0,0 +1,86 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"os"
+
+	"github.com/renproject/pack"
+	"github.com/renproject/tx"
+	"github.com/renproject/tx/txutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// The golden vectors in testdata/vectors.json are the source of truth. They
+// are only regenerated when vectors are deliberately added or changed, and are
+// never compared against the generator, so that changes to the random
+// generators in txutil do not look like changes to hashing.
+//go:generate go run ./cmd/txctl vectors -o testdata/vectors.json
+
+// readGoldenVectors reads the golden vectors from testdata/vectors.json.
+func readGoldenVectors() []txutil.TestVector {
+	f, err := os.Open("testdata/vectors.json")
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	vectors, err := txutil.ReadTestVectors(f)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(vectors).ToNot(BeEmpty())
+	return vectors
+}
+
+var _ = Describe("Test vectors", func() {
+
+	readVectors := readGoldenVectors
+
+	Context("when checking the golden vectors", func() {
+		It("should match the hashes computed by this package", func() {
+			for _, vector := range readVectors() {
+				Expect(vector.Check()).To(Succeed())
+			}
+		})
+
+		It("should cover every selector", func() {
+			names := map[string]bool{}
+			for _, vector := range readVectors() {
+				names[vector.Name] = true
+			}
+			for _, selector := range txutil.AllSelectors() {
+				Expect(names).To(HaveKey(string(selector)))
+			}
+		})
+
+		It("should match the hashes recomputed from the stored inputs", func() {
+			for _, vector := range readVectors() {
+				input := pack.Typed{}
+				Expect(input.UnmarshalJSON(vector.Input)).To(Succeed(), vector.Name)
+				hash, err := tx.NewTxHash(vector.Version, vector.Selector, input)
+				Expect(err).ToNot(HaveOccurred(), vector.Name)
+				Expect(hex.EncodeToString(hash[:])).To(Equal(vector.Hash), vector.Name)
+			}
+		})
+	})
+
+	Context("when a vector drifts", func() {
+		It("should return an error", func() {
+			vector := readVectors()[0]
+			Expect(vector.Check()).To(Succeed())
+
+			drifted := vector
+			drifted.Hash = hex.EncodeToString(make([]byte, 32))
+			Expect(drifted.Check()).ToNot(Succeed())
+
+			drifted = vector
+			drifted.Surge = vector.Surge + "00"
+			Expect(drifted.Check()).ToNot(Succeed())
+
+			drifted = vector
+			drifted.Version = tx.Version0
+			Expect(drifted.Check()).ToNot(Succeed())
+
+			drifted = vector
+			drifted.Selector = "BTC/toSolana"
+			Expect(drifted.Check()).ToNot(Succeed())
+		})
+	})
+})